@@ -0,0 +1,266 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// alpinePackages maps tools to apk packages. Go, Neovim and Node come from
+// apk rather than upstream tarballs and nvm because those builds are linked
+// against glibc and don't run on musl.
+var alpinePackages = map[string][]string{
+	"zsh":       {"zsh", "curl", "git"},
+	"go":        {"go"},
+	"make":      {"make"},
+	"gcc":       {"gcc", "musl-dev"},
+	"unzip":     {"unzip"},
+	"docker":    {"docker", "docker-cli-compose"},
+	"tmux":      {"tmux"},
+	"node":      {"nodejs", "npm"},
+	"python":    {"python3", "py3-pip", "pipx"},
+	"poetry":    {"pipx"},
+	"neovim":    {"neovim"},
+	"bitwarden": {"nodejs", "npm"},
+	"starship":  {"starship"},
+	"zoxide":    {"zoxide"},
+	"direnv":    {"direnv"},
+	"rust":      {"curl", "gcc", "musl-dev"},
+}
+
+type AlpineTools struct {
+	tools []string
+	pm    PackageManager
+	// custom are the custom tools that can be selected
+	custom []CustomTool
+}
+
+func (t *AlpineTools) Run() error {
+	t.pm.Update()
+
+	if err := t.pm.Install(BatchPackages(t.tools, alpinePackages)...); err != nil {
+		return err
+	}
+
+	for _, tool := range t.tools {
+		color.Blue("Setting up %s...", tool)
+		var err error
+
+		switch tool {
+		case "zsh":
+			err = t.InstallZsh()
+		case "go":
+			err = t.InstallGo()
+		case "make":
+			err = t.InstallMake()
+		case "gcc":
+			err = t.InstallGcc()
+		case "unzip":
+			err = t.InstallUnzip()
+		case "docker":
+			err = t.InstallDocker()
+		case "tmux":
+			err = t.InstallTmux()
+		case "node":
+			err = t.InstallNode()
+		case "python":
+			err = t.InstallPython()
+		case "neovim":
+			err = t.InstallNeovim()
+		case "poetry":
+			err = t.InstallPoetry()
+		case "bitwarden":
+			err = t.InstallBitwarden()
+		case "starship":
+			err = t.InstallStarship()
+		case "zoxide":
+			err = t.InstallZoxide()
+		case "direnv":
+			err = t.InstallDirenv()
+		case "rust":
+			err = t.InstallRust()
+		case "releases":
+			err = t.InstallReleases()
+		default:
+			err = InstallCustomTool(t.pm, "Alpine", tool, t.custom)
+		}
+
+		if err != nil {
+			color.Red("Error installing %s: %v", tool, err)
+			return err
+		}
+
+		color.Green("%s installed and configured successfully", tool)
+	}
+
+	return nil
+}
+
+func (a *AlpineTools) InstallNeovim() error {
+	color.Blue("Installing Neovim...")
+	if err := a.pm.Install(alpinePackages["neovim"]...); err != nil {
+		return err
+	}
+
+	return a.ConfigureNeovim()
+}
+
+func (a *AlpineTools) InstallZsh() error {
+	color.Blue("Installing Zsh...")
+	// curl and git are needed for installing Oh My Zsh and its plugins
+	if err := a.pm.Install(alpinePackages["zsh"]...); err != nil {
+		return err
+	}
+
+	// Change default shell to Zsh
+	if err := SetLoginShell("zsh"); err != nil {
+		return err
+	}
+
+	// Install Oh My Zsh
+	return a.InstallOhMyZsh()
+}
+
+func (a *AlpineTools) InstallGcc() error {
+	color.Blue("Installing GCC...")
+	return a.pm.Install(alpinePackages["gcc"]...)
+}
+
+func (a *AlpineTools) InstallMake() error {
+	color.Blue("Installing Make...")
+	return a.pm.Install(alpinePackages["make"]...)
+}
+
+func (a *AlpineTools) InstallUnzip() error {
+	color.Blue("Installing Unzip...")
+	return a.pm.Install(alpinePackages["unzip"]...)
+}
+
+func (a *AlpineTools) InstallOhMyZsh() error {
+	return SetupOhMyZsh()
+}
+
+func (a *AlpineTools) InstallDocker() error {
+	color.Blue("Installing Docker...")
+	if err := a.pm.Install(alpinePackages["docker"]...); err != nil {
+		return err
+	}
+
+	// Containers usually run without OpenRC, in which case the host's docker
+	// socket is expected to be mounted instead
+	color.Blue("Enabling Docker service...")
+	if err := a.runCommand(Privileged("rc-update add docker default")); err != nil {
+		color.Red("Error enabling Docker service: %v", err)
+	}
+
+	return nil
+}
+
+func (a *AlpineTools) InstallTmux() error {
+	color.Blue("Installing Tmux...")
+	if err := a.pm.Install(alpinePackages["tmux"]...); err != nil {
+		return err
+	}
+
+	if err := installTmuxSessionizer(); err != nil {
+		return err
+	}
+
+	return ConfigureTmux(a.pm)
+}
+
+func (a *AlpineTools) InstallGo() error {
+	color.Blue("Installing Go...")
+	if err := a.pm.Install(alpinePackages["go"]...); err != nil {
+		return err
+	}
+
+	return SetupGoTools()
+}
+
+func (a *AlpineTools) InstallNode() error {
+	color.Blue("Installing Node...")
+	if err := a.pm.Install(alpinePackages["node"]...); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	return ConfigureNode(config.Node)
+}
+
+func (a *AlpineTools) InstallPython() error {
+	color.Blue("Installing Python...")
+	if err := a.pm.Install(alpinePackages["python"]...); err != nil {
+		return err
+	}
+
+	return SetupPython()
+}
+
+func (a *AlpineTools) ConfigureNeovim() error {
+	color.Blue("Configuring Neovim...")
+	return a.runCommand("git clone https://github.com/tedraykov/init.lua.git", filepath.Join(HomePath(), ".config", "nvim"))
+}
+
+func (a *AlpineTools) InstallPoetry() error {
+	color.Blue("Installing Poetry...")
+	if err := a.pm.Install(alpinePackages["poetry"]...); err != nil {
+		return err
+	}
+
+	return SetupPoetry()
+}
+
+func (a *AlpineTools) InstallBitwarden() error {
+	color.Blue("Installing Bitwarden...")
+	if err := a.pm.Install(alpinePackages["bitwarden"]...); err != nil {
+		return err
+	}
+
+	return InstallNpmPackages("@bitwarden/cli")
+}
+
+func (a *AlpineTools) InstallStarship() error {
+	color.Blue("Installing Starship...")
+	if err := a.pm.Install(alpinePackages["starship"]...); err != nil {
+		return err
+	}
+
+	return ConfigureStarship()
+}
+
+func (a *AlpineTools) InstallZoxide() error {
+	color.Blue("Installing Zoxide...")
+	if err := a.pm.Install(alpinePackages["zoxide"]...); err != nil {
+		return err
+	}
+
+	return ConfigureZoxide()
+}
+
+func (a *AlpineTools) InstallDirenv() error {
+	color.Blue("Installing Direnv...")
+	if err := a.pm.Install(alpinePackages["direnv"]...); err != nil {
+		return err
+	}
+
+	return ConfigureDirenv()
+}
+
+func (a *AlpineTools) InstallRust() error {
+	color.Blue("Installing Rust...")
+	return SetupRust()
+}
+
+func (a *AlpineTools) InstallReleases() error {
+	color.Blue("Installing GitHub releases...")
+	return SetupReleases()
+}
+
+func (a *AlpineTools) runCommand(args ...string) error {
+	return RunCommand(args...)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// archPackages maps every tool to the pacman packages that provide it, so
+// Arch needs neither an AUR helper nor upstream install scripts.
+var archPackages = map[string][]string{
+	"zsh":       {"zsh"},
+	"go":        {"go"},
+	"make":      {"make"},
+	"gcc":       {"gcc"},
+	"unzip":     {"unzip"},
+	"docker":    {"docker", "docker-buildx", "docker-compose"},
+	"tmux":      {"tmux"},
+	"python":    {"python", "python-pipx"},
+	"poetry":    {"python-pipx"},
+	"neovim":    {"neovim"},
+	"bitwarden": {"bitwarden-cli"},
+	"starship":  {"starship"},
+	"zoxide":    {"zoxide"},
+	"direnv":    {"direnv"},
+}
+
+type ArchTools struct {
+	tools []string
+	pm    PackageManager
+	// custom are the custom tools that can be selected
+	custom []CustomTool
+}
+
+func (t *ArchTools) Run() error {
+	t.pm.Update()
+
+	if err := t.pm.Install(BatchPackages(t.tools, archPackages)...); err != nil {
+		return err
+	}
+
+	for _, tool := range t.tools {
+		color.Blue("Setting up %s...", tool)
+		var err error
+
+		switch tool {
+		case "zsh":
+			err = t.InstallZsh()
+		case "go":
+			err = t.InstallGo()
+		case "make":
+			err = t.InstallMake()
+		case "gcc":
+			err = t.InstallGcc()
+		case "unzip":
+			err = t.InstallUnzip()
+		case "docker":
+			err = t.InstallDocker()
+		case "tmux":
+			err = t.InstallTmux()
+		case "node":
+			err = t.InstallNode()
+		case "python":
+			err = t.InstallPython()
+		case "neovim":
+			err = t.InstallNeovim()
+		case "poetry":
+			err = t.InstallPoetry()
+		case "bitwarden":
+			err = t.InstallBitwarden()
+		case "starship":
+			err = t.InstallStarship()
+		case "zoxide":
+			err = t.InstallZoxide()
+		case "direnv":
+			err = t.InstallDirenv()
+		case "rust":
+			err = t.InstallRust()
+		case "releases":
+			err = t.InstallReleases()
+		default:
+			err = InstallCustomTool(t.pm, "Arch", tool, t.custom)
+		}
+
+		if err != nil {
+			color.Red("Error installing %s: %v", tool, err)
+			return err
+		}
+
+		color.Green("%s installed and configured successfully", tool)
+	}
+
+	return nil
+}
+
+func (a *ArchTools) InstallNeovim() error {
+	color.Blue("Installing Neovim...")
+	if err := a.pm.Install(archPackages["neovim"]...); err != nil {
+		return err
+	}
+
+	return a.ConfigureNeovim()
+}
+
+func (a *ArchTools) InstallZsh() error {
+	color.Blue("Installing Zsh...")
+	if err := a.pm.Install(archPackages["zsh"]...); err != nil {
+		return err
+	}
+
+	// Change default shell to Zsh
+	if err := SetLoginShell("zsh"); err != nil {
+		return err
+	}
+
+	// Install Oh My Zsh
+	return a.InstallOhMyZsh()
+}
+
+func (a *ArchTools) InstallGcc() error {
+	color.Blue("Installing GCC...")
+	return a.pm.Install(archPackages["gcc"]...)
+}
+
+func (a *ArchTools) InstallMake() error {
+	color.Blue("Installing Make...")
+	return a.pm.Install(archPackages["make"]...)
+}
+
+func (a *ArchTools) InstallUnzip() error {
+	color.Blue("Installing Unzip...")
+	return a.pm.Install(archPackages["unzip"]...)
+}
+
+func (a *ArchTools) InstallOhMyZsh() error {
+	return SetupOhMyZsh()
+}
+
+func (a *ArchTools) InstallDocker() error {
+	color.Blue("Installing Docker...")
+	if err := a.pm.Install(archPackages["docker"]...); err != nil {
+		return err
+	}
+
+	// Arch doesn't start services on install
+	if err := a.runCommand("sudo systemctl enable --now docker.service"); err != nil {
+		return err
+	}
+
+	color.Blue("Running Docker post-installation configuration...")
+	if err := a.runCommand("sudo usermod -aG docker", os.Getenv("USER")); err != nil {
+		color.Red("Error running Docker post-installation configuration: %v", err)
+	}
+
+	return nil
+}
+
+func (a *ArchTools) InstallTmux() error {
+	color.Blue("Installing Tmux...")
+	if err := a.pm.Install(archPackages["tmux"]...); err != nil {
+		return err
+	}
+
+	if err := installTmuxSessionizer(); err != nil {
+		return err
+	}
+
+	return ConfigureTmux(a.pm)
+}
+
+func (a *ArchTools) InstallGo() error {
+	color.Blue("Installing Go...")
+	if err := a.pm.Install(archPackages["go"]...); err != nil {
+		return err
+	}
+
+	return SetupGoTools()
+}
+
+func (a *ArchTools) InstallNode() error {
+	color.Blue("Installing Node...")
+	return SetupNode()
+}
+
+func (a *ArchTools) InstallPython() error {
+	color.Blue("Installing Python...")
+	if err := a.pm.Install(archPackages["python"]...); err != nil {
+		return err
+	}
+
+	return SetupPython()
+}
+
+func (a *ArchTools) ConfigureNeovim() error {
+	color.Blue("Configuring Neovim...")
+	return a.runCommand("git clone https://github.com/tedraykov/init.lua.git", filepath.Join(HomePath(), ".config", "nvim"))
+}
+
+func (a *ArchTools) InstallPoetry() error {
+	color.Blue("Installing Poetry...")
+	if err := a.pm.Install(archPackages["poetry"]...); err != nil {
+		return err
+	}
+
+	return SetupPoetry()
+}
+
+func (a *ArchTools) InstallBitwarden() error {
+	color.Blue("Installing Bitwarden...")
+	return a.pm.Install(archPackages["bitwarden"]...)
+}
+
+func (a *ArchTools) InstallStarship() error {
+	color.Blue("Installing Starship...")
+	if err := a.pm.Install(archPackages["starship"]...); err != nil {
+		return err
+	}
+
+	return ConfigureStarship()
+}
+
+func (a *ArchTools) InstallZoxide() error {
+	color.Blue("Installing Zoxide...")
+	if err := a.pm.Install(archPackages["zoxide"]...); err != nil {
+		return err
+	}
+
+	return ConfigureZoxide()
+}
+
+func (a *ArchTools) InstallDirenv() error {
+	color.Blue("Installing Direnv...")
+	if err := a.pm.Install(archPackages["direnv"]...); err != nil {
+		return err
+	}
+
+	return ConfigureDirenv()
+}
+
+func (a *ArchTools) InstallRust() error {
+	color.Blue("Installing Rust...")
+	return SetupRust()
+}
+
+func (a *ArchTools) InstallReleases() error {
+	color.Blue("Installing GitHub releases...")
+	return SetupReleases()
+}
+
+func (a *ArchTools) runCommand(args ...string) error {
+	return RunCommand(args...)
+}
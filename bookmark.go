@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const bookmarkUsage = "usage: devtools bookmark add <key> <path> | list | remove <key>"
+
+// BookmarkCommand runs `devtools bookmark`, which manages the project
+// bookmarks bound to keys in the tmux config.
+func BookmarkCommand(config *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(bookmarkUsage)
+	}
+
+	bookmarks := config.Tmux.Bookmarks
+
+	switch args[0] {
+	case "list":
+		if len(bookmarks) == 0 {
+			fmt.Println("No bookmarks.")
+		}
+		for _, bookmark := range bookmarks {
+			fmt.Printf("%s\t%s\n", bookmark.Key, bookmark.Path)
+		}
+		return nil
+	case "add":
+		if len(args) != 3 {
+			return errors.New(bookmarkUsage)
+		}
+
+		bookmark, err := newBookmark(config, args[1], args[2])
+		if err != nil {
+			return err
+		}
+
+		user, err := LoadUserBookmarks()
+		if err != nil {
+			return err
+		}
+
+		return saveBookmarks(AddBookmark(user, bookmark))
+	case "remove":
+		if len(args) != 2 {
+			return errors.New(bookmarkUsage)
+		}
+
+		user, err := LoadUserBookmarks()
+		if err != nil {
+			return err
+		}
+
+		updated, ok := RemoveBookmark(user, args[1])
+		if !ok {
+			if _, team := RemoveBookmark(bookmarks, args[1]); team {
+				return fmt.Errorf("the bookmark bound to %s comes from the team config", args[1])
+			}
+			return fmt.Errorf("no bookmark bound to %s", args[1])
+		}
+
+		return saveBookmarks(updated, args[1])
+	default:
+		return errors.New(bookmarkUsage)
+	}
+}
+
+func newBookmark(config *Config, key, path string) (Bookmark, error) {
+	if strings.TrimSpace(key) == "" {
+		return Bookmark{}, fmt.Errorf("bookmark key can't be empty")
+	}
+
+	reserved, err := reservedTmuxKeys(config)
+	if err != nil {
+		return Bookmark{}, err
+	}
+	if slices.Contains(reserved, key) {
+		return Bookmark{}, fmt.Errorf("%s is already bound in the tmux config", key)
+	}
+
+	path, err = filepath.Abs(ExpandHome(path))
+	if err != nil {
+		return Bookmark{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Bookmark{}, fmt.Errorf("failed to bookmark %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return Bookmark{}, fmt.Errorf("failed to bookmark %s: not a directory", path)
+	}
+
+	return Bookmark{Key: key, Path: path}, nil
+}
+
+// AddBookmark adds bookmark, replacing any bookmark bound to the same key.
+func AddBookmark(bookmarks []Bookmark, bookmark Bookmark) []Bookmark {
+	updated, _ := RemoveBookmark(bookmarks, bookmark.Key)
+	return append(updated, bookmark)
+}
+
+// RemoveBookmark removes the bookmark bound to key, reporting whether there
+// was one.
+func RemoveBookmark(bookmarks []Bookmark, key string) ([]Bookmark, bool) {
+	updated := []Bookmark{}
+	found := false
+
+	for _, bookmark := range bookmarks {
+		if bookmark.Key == key {
+			found = true
+			continue
+		}
+		updated = append(updated, bookmark)
+	}
+
+	return updated, found
+}
+
+// saveBookmarks stores the user's bookmarks in their config and brings the
+// tmux config, which also has the team's bookmarks, and any running tmux
+// server up to date.
+func saveBookmarks(user []Bookmark, unbind ...string) error {
+	if err := SetConfigValue([]string{"tmux", "bookmarks"}, user); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := UpdateTmuxBookmarks(config.Tmux.Bookmarks); err != nil {
+		return err
+	}
+
+	return ReloadTmux(unbind...)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBookmarksKeepTeamLayer(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+
+	team := filepath.Join(t.TempDir(), "team.yaml")
+	t.Setenv("DEVTOOLS_TEAM_CONFIG", team)
+	writeTeamBookmarks := func(paths ...string) {
+		content := "tmux:\n  bookmarks:\n"
+		for i, path := range paths {
+			content += "    - key: " + string(rune('A'+i)) + "\n      path: " + path + "\n"
+		}
+		if err := os.WriteFile(team, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTeamBookmarks("/team/api")
+
+	project := filepath.Join(home, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := BookmarkCommand(config, []string{"add", "U", project}); err != nil {
+		t.Fatalf("bookmark add failed: %v", err)
+	}
+
+	user, err := LoadUserBookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(user) != 1 || user[0] != (Bookmark{Key: "U", Path: project}) {
+		t.Errorf("user config has bookmarks %v, want only U", user)
+	}
+
+	// A later team change still shows up next to the user's bookmark
+	writeTeamBookmarks("/team/api", "/team/web")
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{}
+	for _, bookmark := range config.Tmux.Bookmarks {
+		keys = append(keys, bookmark.Key)
+	}
+	if strings.Join(keys, ",") != "A,B,U" {
+		t.Errorf("merged bookmarks %v, want A, B and U", keys)
+	}
+
+	if err := BookmarkCommand(config, []string{"remove", "A"}); err == nil {
+		t.Error("removed a bookmark from the team config")
+	}
+}
+
+func TestBookmarkRejectsBoundKeys(t *testing.T) {
+	config := DefaultConfig()
+	config.Tmux.Prefix = "C-a"
+
+	for _, key := range []string{"f", "h", "j", "k", "l", "C-a"} {
+		if _, err := newBookmark(config, key, t.TempDir()); err == nil {
+			t.Errorf("bookmark on %s was accepted", key)
+		}
+	}
+
+	if _, err := newBookmark(config, "H", t.TempDir()); err != nil {
+		t.Errorf("bookmark on H was rejected: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Clipboard is a way for tmux copy mode to reach the system clipboard.
+type Clipboard struct {
+	Name string
+	// Command receives the copied text on stdin. It is empty for OSC 52,
+	// where tmux hands the text to the terminal itself.
+	Command string
+	// Package provides the command when it isn't installed
+	Package string
+}
+
+var (
+	clipboardPbcopy = Clipboard{Name: "pbcopy", Command: "pbcopy"}
+	clipboardWlCopy = Clipboard{Name: "wl-copy", Command: "wl-copy", Package: "wl-clipboard"}
+	clipboardXclip  = Clipboard{Name: "xclip", Command: "xclip -in -selection clipboard", Package: "xclip"}
+	clipboardXsel   = Clipboard{Name: "xsel", Command: "xsel --clipboard --input", Package: "xsel"}
+	clipboardOSC52  = Clipboard{Name: "osc52"}
+)
+
+// clipboardCandidates lists the clipboards that fit the current session,
+// most preferred first.
+func clipboardCandidates() []Clipboard {
+	switch {
+	case runtime.GOOS == "darwin":
+		return []Clipboard{clipboardPbcopy}
+	case IsWSL():
+		return []Clipboard{{Name: "windows", Command: WSLCopyCommand()}}
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		return []Clipboard{clipboardWlCopy}
+	case os.Getenv("DISPLAY") != "":
+		return []Clipboard{clipboardXclip, clipboardXsel}
+	default:
+		return nil
+	}
+}
+
+// DetectClipboard picks the clipboard for tmux copy mode. When no helper
+// for the session is installed, the preferred one is installed with pm if
+// install is set; otherwise it falls back to OSC 52, which works over SSH
+// and on headless servers as long as the terminal supports it.
+func DetectClipboard(pm PackageManager, install bool) Clipboard {
+	candidates := clipboardCandidates()
+
+	for _, clipboard := range candidates {
+		name := strings.Fields(clipboard.Command)[0]
+		if _, err := exec.LookPath(name); err == nil {
+			return clipboard
+		}
+	}
+
+	if install && len(candidates) > 0 && candidates[0].Package != "" {
+		color.Blue("Installing %s for tmux copy mode...", candidates[0].Name)
+		if err := pm.Install(candidates[0].Package); err == nil {
+			return candidates[0]
+		}
+		color.Red("Error installing %s, falling back to OSC 52", candidates[0].Package)
+	}
+
+	return clipboardOSC52
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+)
+
+// runSubcommand runs one of the non-interactive devtools commands, e.g.
+// `devtools sessionize [path]` or `devtools bookmark list`.
+func runSubcommand(name string, args []string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "sessionize":
+		path := ""
+		if len(args) > 0 {
+			path = args[0]
+		}
+		return Sessionize(config, path)
+	case "bookmark":
+		return BookmarkCommand(config, args)
+	case "go":
+		return GoToolchainCommand(config, args)
+	case "release":
+		return ReleaseCommand(config, args)
+	case "plugin":
+		return PluginCommand(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
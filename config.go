@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the devtools configuration. The built-in defaults are overlaid
+// with the team config and then the user's config.yaml, so each layer only
+// needs the settings it changes.
+type Config struct {
+	// Keymap names the preset of movement keys used by the TUI and tmux:
+	// vim, shifted or arrows
+	Keymap      string            `yaml:"keymap"`
+	Sessionizer SessionizerConfig `yaml:"sessionizer"`
+	Tmux        TmuxSettings      `yaml:"tmux"`
+	Zsh         ZshSettings       `yaml:"zsh"`
+	Starship    StarshipSettings  `yaml:"starship"`
+	Go          GoSettings        `yaml:"go"`
+	Node        NodeSettings      `yaml:"node"`
+	Python      PythonSettings    `yaml:"python"`
+	Rust        RustSettings      `yaml:"rust"`
+	Neovim      NeovimSettings    `yaml:"neovim"`
+	// Releases are binaries installed from GitHub releases
+	Releases []GitHubRelease `yaml:"releases"`
+	// Tools are custom tools offered next to the built-in ones
+	Tools []CustomTool `yaml:"tools"`
+}
+
+// defaultTeamConfigPath is where a team-wide config is read from unless
+// DEVTOOLS_TEAM_CONFIG points elsewhere.
+const defaultTeamConfigPath = "/etc/devtools/config.yaml"
+
+type SessionizerConfig struct {
+	// Roots are the directories searched for projects
+	Roots []ProjectRoot `yaml:"roots"`
+	// Depth is the default number of levels below a root offered as projects
+	Depth int `yaml:"depth"`
+	// Ignore holds globs matched against directory names and paths relative
+	// to the root; matching directories are neither listed nor searched
+	Ignore []string `yaml:"ignore"`
+}
+
+type ProjectRoot struct {
+	Path string `yaml:"path"`
+	// Depth overrides SessionizerConfig.Depth for this root when set
+	Depth int `yaml:"depth,omitempty"`
+	// GitOnly only offers git repositories and worktrees as projects
+	GitOnly bool `yaml:"git_only,omitempty"`
+}
+
+// UnmarshalYAML also accepts a root written as a plain path.
+func (r *ProjectRoot) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		r.Path = node.Value
+		return nil
+	}
+
+	type plain ProjectRoot
+	return node.Decode((*plain)(r))
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Keymap: defaultKeymap,
+		Sessionizer: SessionizerConfig{
+			Roots:  []ProjectRoot{{Path: "~/dev"}},
+			Depth:  2,
+			Ignore: []string{"node_modules", "vendor"},
+		},
+		Tmux:     DefaultTmuxSettings(),
+		Zsh:      DefaultZshSettings(),
+		Starship: DefaultStarshipSettings(),
+		Go:       DefaultGoSettings(),
+		Node:     DefaultNodeSettings(),
+		Python:   DefaultPythonSettings(),
+		Rust:     DefaultRustSettings(),
+		Neovim:   DefaultNeovimSettings(),
+		Releases: DefaultReleases(),
+	}
+}
+
+func (c *Config) KeymapPreset() (Keymap, error) {
+	return LookupKeymap(c.Keymap)
+}
+
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "devtools")
+	}
+
+	return filepath.Join(HomePath(), ".config", "devtools")
+}
+
+// StateDir holds data devtools records on its own, like session usage.
+func StateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "devtools")
+	}
+
+	return filepath.Join(HomePath(), ".local", "state", "devtools")
+}
+
+// DataDir holds the tools devtools installs for the user.
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "devtools")
+	}
+
+	return filepath.Join(HomePath(), ".local", "share", "devtools")
+}
+
+func ConfigPath() string {
+	return filepath.Join(ConfigDir(), "config.yaml")
+}
+
+func TeamConfigPath() string {
+	if path := os.Getenv("DEVTOOLS_TEAM_CONFIG"); path != "" {
+		return path
+	}
+
+	return defaultTeamConfigPath
+}
+
+// LoadConfig reads the team and user config files on top of the defaults.
+// Missing files aren't an error.
+func LoadConfig() (*Config, error) {
+	config := DefaultConfig()
+
+	for _, path := range []string{TeamConfigPath(), ConfigPath()} {
+		// Bookmarks add up across the layers rather than replace each other,
+		// so a user's bookmarks don't hide the team's
+		bookmarks := config.Tmux.Bookmarks
+		config.Tmux.Bookmarks = nil
+
+		if err := mergeConfigFile(config, path); err != nil {
+			return nil, err
+		}
+
+		for _, bookmark := range config.Tmux.Bookmarks {
+			bookmarks = AddBookmark(bookmarks, bookmark)
+		}
+		config.Tmux.Bookmarks = bookmarks
+	}
+
+	return config, nil
+}
+
+// LoadUserBookmarks reads only the bookmarks of the user's config file,
+// which is where `devtools bookmark` writes.
+func LoadUserBookmarks() ([]Bookmark, error) {
+	user := struct {
+		Tmux struct {
+			Bookmarks []Bookmark `yaml:"bookmarks"`
+		} `yaml:"tmux"`
+	}{}
+
+	content, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConfigPath(), err)
+	}
+
+	return user.Tmux.Bookmarks, nil
+}
+
+func mergeConfigFile(config *Config, path string) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// SetConfigValue sets the value at the path of keys in the user's config
+// file, creating missing mappings. The rest of the file, including its
+// comments, is kept as it is.
+func SetConfigValue(keys []string, value any) error {
+	doc := &yaml.Node{}
+
+	content, err := os.ReadFile(ConfigPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", ConfigPath(), err)
+	}
+
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+
+	node := doc.Content[0]
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("failed to set %s: %s is not a mapping", strings.Join(keys, "."), key)
+		}
+
+		var child *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				child = node.Content[i+1]
+			}
+		}
+
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+
+		node = child
+	}
+
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", strings.Join(keys, "."), err)
+	}
+
+	var updated strings.Builder
+	encoder := yaml.NewEncoder(&updated)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(ConfigDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return WriteContentToFile(updated.String(), ConfigPath())
+}
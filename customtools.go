@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// CustomTool is a tool defined in the config rather than built into
+// devtools. Packages and Commands are keyed by OS as in the OS menu,
+// lowercased, e.g. ubuntu or macos; the default key applies to the others.
+type CustomTool struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Packages are installed with the OS package manager before Commands run
+	Packages map[string][]string `yaml:"packages"`
+	// Commands are shell commands that install the tool
+	Commands map[string][]string `yaml:"commands"`
+	// Verify is a shell command that succeeds when the tool is installed.
+	// Installed tools are skipped, and a failing Verify after the install
+	// fails it. Without Verify a tool is installed once
+	Verify string `yaml:"verify"`
+	// Requires names the tools, built in or custom, installed before this one
+	Requires []string `yaml:"requires"`
+	// Plugin is the executable of a plugin providing the tool, which then
+	// takes care of its install
+	Plugin string `yaml:"-"`
+}
+
+// CustomTools returns the tools from the config followed by the tools
+// plugins provide. Plugins named like a built-in or configured tool, or
+// requiring a tool that doesn't exist, are skipped with a warning, so a
+// stray plugin on PATH can't keep devtools from starting.
+func CustomTools(config *Config, builtin []string) []CustomTool {
+	names := map[string]bool{}
+	for _, name := range builtin {
+		names[name] = true
+	}
+	for _, tool := range config.Tools {
+		names[tool.Name] = true
+	}
+
+	plugins := []CustomTool{}
+	for _, plugin := range DiscoverPlugins() {
+		if names[plugin.Name] {
+			color.Yellow("Skipping plugin %s: a tool with that name is already defined", plugin.Plugin)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return append(append([]CustomTool{}, config.Tools...), dropUnresolvedPlugins(plugins, names)...)
+}
+
+// dropUnresolvedPlugins drops the plugins requiring tools that are neither
+// in names nor provided by another plugin that is kept.
+func dropUnresolvedPlugins(plugins []CustomTool, names map[string]bool) []CustomTool {
+	for {
+		known := map[string]bool{}
+		for name := range names {
+			known[name] = true
+		}
+		for _, plugin := range plugins {
+			known[plugin.Name] = true
+		}
+
+		kept := []CustomTool{}
+		for _, plugin := range plugins {
+			unknown := ""
+			for _, required := range plugin.Requires {
+				if !known[required] {
+					unknown = required
+					break
+				}
+			}
+
+			if unknown != "" {
+				color.Yellow("Skipping plugin %s: it requires unknown tool %s", plugin.Plugin, unknown)
+				continue
+			}
+			kept = append(kept, plugin)
+		}
+
+		// Dropping a plugin may leave others requiring it
+		if len(kept) == len(plugins) {
+			return kept
+		}
+		plugins = kept
+	}
+}
+
+// forOS returns the entry of a per-OS map for osName, falling back to the
+// default entry.
+func forOS(values map[string][]string, osName string) []string {
+	if value, ok := values[strings.ToLower(osName)]; ok {
+		return value
+	}
+
+	return values["default"]
+}
+
+func customToolStatePath() string {
+	return filepath.Join(StateDir(), "tools.json")
+}
+
+func findCustomTool(tools []CustomTool, name string) (CustomTool, bool) {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+
+	return CustomTool{}, false
+}
+
+// ValidateCustomTools checks that custom tools have unique names that
+// don't shadow built-in tools, and only require tools that exist.
+func ValidateCustomTools(tools []CustomTool, builtin []string) error {
+	names := map[string]bool{}
+	for _, name := range builtin {
+		names[name] = true
+	}
+
+	for _, tool := range tools {
+		if tool.Name == "" {
+			return fmt.Errorf("custom tool without a name")
+		}
+		if names[tool.Name] {
+			return fmt.Errorf("custom tool %s is already defined", tool.Name)
+		}
+		names[tool.Name] = true
+	}
+
+	for _, tool := range tools {
+		for _, required := range tool.Requires {
+			if !names[required] {
+				return fmt.Errorf("custom tool %s requires unknown tool %s", tool.Name, required)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResolveToolOrder adds the tools the selected custom tools require and
+// orders the selection so every tool comes after its requirements. The
+// order of the selection is kept otherwise.
+func ResolveToolOrder(selected []string, tools []CustomTool) ([]string, error) {
+	ordered := []string{}
+	done := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if done[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("tool %s requires itself through its requirements", name)
+		}
+		visiting[name] = true
+
+		if tool, ok := findCustomTool(tools, name); ok {
+			for _, required := range tool.Requires {
+				if err := visit(required); err != nil {
+					return err
+				}
+			}
+		}
+
+		visiting[name] = false
+		done[name] = true
+		ordered = append(ordered, name)
+		return nil
+	}
+
+	for _, name := range selected {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// InstallCustomTool installs the custom tool called name, from the config
+// or a plugin, for osName. tools are the custom tools found at startup.
+func InstallCustomTool(pm PackageManager, osName, name string, tools []CustomTool) error {
+	tool, ok := findCustomTool(tools, name)
+	if !ok {
+		return fmt.Errorf("%s is not a valid tool", name)
+	}
+
+	state, err := loadCustomToolState()
+	if err != nil {
+		return err
+	}
+
+	if tool.Plugin != "" {
+		err = InstallPlugin(tool, osName)
+	} else {
+		err = installConfiguredTool(pm, osName, tool, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	state[name] = time.Now()
+	return saveCustomToolState(state)
+}
+
+// installConfiguredTool installs a tool from the config with the packages
+// and commands for osName.
+func installConfiguredTool(pm PackageManager, osName string, tool CustomTool, state map[string]time.Time) error {
+	name := tool.Name
+
+	if tool.Verify != "" {
+		if verifyCustomTool(tool) == nil {
+			fmt.Printf("%s is already installed, skipping.\n", name)
+			return nil
+		}
+	} else if _, ok := state[name]; ok {
+		fmt.Printf("%s is already installed, skipping.\n", name)
+		return nil
+	}
+
+	packages := forOS(tool.Packages, osName)
+	commands := forOS(tool.Commands, osName)
+	if len(packages) == 0 && len(commands) == 0 {
+		return fmt.Errorf("%s has no install steps for %s", name, osName)
+	}
+
+	color.Blue("Installing %s...", name)
+	if len(packages) > 0 {
+		if err := pm.Install(packages...); err != nil {
+			return err
+		}
+	}
+
+	for _, command := range commands {
+		if err := RunShell(command); err != nil {
+			return fmt.Errorf("failed to install %s: %w", name, err)
+		}
+	}
+
+	if tool.Verify != "" {
+		if err := verifyCustomTool(tool); err != nil {
+			return fmt.Errorf("%s failed verification: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyCustomTool runs the verify command quietly.
+func verifyCustomTool(tool CustomTool) error {
+	return exec.Command("bash", "-c", tool.Verify).Run()
+}
+
+// loadCustomToolState returns when each custom tool was installed.
+func loadCustomToolState() (map[string]time.Time, error) {
+	state := map[string]time.Time{}
+
+	content, err := os.ReadFile(customToolStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", customToolStatePath(), err)
+	}
+
+	return state, nil
+}
+
+func saveCustomToolState(state map[string]time.Time) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tool state: %w", err)
+	}
+
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return WriteContentToFile(string(content), customToolStatePath())
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadFile downloads url to dest. When checksum is set the download is
+// verified against it as a hex encoded SHA-256 and removed on mismatch.
+func DownloadFile(url, dest, checksum string) error {
+	fmt.Printf("Downloading %s\n", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	if checksum != "" && !strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), checksum) {
+		os.Remove(dest)
+		return fmt.Errorf("checksum mismatch for %s", url)
+	}
+
+	return nil
+}
+
+// ExtractTarGz extracts a .tar.gz archive into dest, dropping the first
+// strip components of every path like tar --strip-components.
+func ExtractTarGz(src, dest string, strip int) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+
+		target, ok, err := extractTarget(dest, header.Name, strip)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := extractTarEntry(archive, header, dest, target); err != nil {
+			return err
+		}
+	}
+}
+
+func extractTarEntry(archive *tar.Reader, header *tar.Header, dest, target string) error {
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0755)
+	case tar.TypeSymlink:
+		if err := checkSymlinkTarget(dest, target, header.Linkname); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(file, archive); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ExtractZip extracts a .zip archive into dest, dropping the first strip
+// components of every path like ExtractTarGz.
+func ExtractZip(src, dest string, strip int) error {
+	archive, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer archive.Close()
+
+	for _, entry := range archive.File {
+		target, ok, err := extractTarget(dest, entry.Name, strip)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := extractZipEntry(entry, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractZipEntry(entry *zip.File, target string) error {
+	if entry.FileInfo().IsDir() {
+		return os.MkdirAll(target, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	reader, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+	}
+	defer reader.Close()
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, entry.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+	}
+
+	return nil
+}
+
+// extractTarget returns where an archive entry is extracted to, and false
+// for entries stripped away entirely. Entries that would land outside of
+// dest are refused.
+func extractTarget(dest, name string, strip int) (string, bool, error) {
+	parts := strings.Split(strings.Trim(name, "/"), "/")
+	if len(parts) <= strip {
+		return "", false, nil
+	}
+
+	target := filepath.Join(dest, filepath.Join(parts[strip:]...))
+	if !strings.HasPrefix(target, filepath.Clean(dest)+string(filepath.Separator)) {
+		return "", false, fmt.Errorf("refusing to extract %s outside of %s", name, dest)
+	}
+
+	return target, true, nil
+}
+
+// checkSymlinkTarget refuses links that point outside of dest, since later
+// entries could be written through them.
+func checkSymlinkTarget(dest, link, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("refusing to extract %s linking to the absolute path %s", link, linkname)
+	}
+
+	resolved := filepath.Join(filepath.Dir(link), linkname)
+	root := filepath.Clean(dest)
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to extract %s linking to %s outside of %s", link, linkname, dest)
+	}
+
+	return nil
+}
+
+// ReplaceSymlink points link at target, replacing an existing link
+// atomically so the link never goes missing.
+func ReplaceSymlink(target, link string) error {
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, link)
+}
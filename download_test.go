@@ -0,0 +1,64 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTarGz(t *testing.T, path string, headers []tar.Header) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	archive := tar.NewWriter(gz)
+	for _, header := range headers {
+		if err := archive.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractTarGzSymlinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkname string
+		ok       bool
+	}{
+		{"relative inside", "../lib/nvim", true},
+		{"absolute", "/etc/passwd", false},
+		{"relative outside", "../../outside", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "archive.tar.gz")
+			writeTarGz(t, src, []tar.Header{
+				{Name: "nvim/bin/link", Typeflag: tar.TypeSymlink, Linkname: tt.linkname},
+			})
+
+			dest := filepath.Join(dir, "out")
+			err := ExtractTarGz(src, dest, 1)
+			if tt.ok && err != nil {
+				t.Errorf("ExtractTarGz failed: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Errorf("ExtractTarGz extracted a link to %s", tt.linkname)
+			}
+		})
+	}
+}
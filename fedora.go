@@ -0,0 +1,376 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// fedoraPackages maps tools to the dnf packages that can be installed
+// together in a single batch before the per-tool setup runs.
+var fedoraPackages = map[string][]string{
+	"zsh":    {"zsh"},
+	"go":     {"golang"},
+	"unzip":  {"unzip"},
+	"tmux":   {"tmux"},
+	"python": {"python3", "pipx"},
+	"poetry": {"pipx"},
+	"neovim": {"neovim"},
+	"zoxide": {"zoxide"},
+	"direnv": {"direnv"},
+}
+
+// fedoraDevelopmentTools is the id of the "Development Tools" group, which
+// provides both gcc and make. RHEL and its rebuilds call it development.
+const (
+	fedoraDevelopmentTools = "development-tools"
+	rhelDevelopmentTools   = "development"
+)
+
+// dockerRepoURL is the Docker CE repo file for a distribution Docker
+// publishes packages for, e.g. fedora or rhel.
+const dockerRepoURL = "https://download.docker.com/linux/%s/docker-ce.repo"
+
+type FedoraTools struct {
+	tools []string
+	pm    *Dnf
+	// id is the os-release ID, since the backend also covers RHEL and its
+	// rebuilds
+	id string
+	// custom are the custom tools that can be selected
+	custom []CustomTool
+	// developmentTools is set once the Development Tools group is installed
+	developmentTools bool
+}
+
+// rhel reports whether the system is RHEL or one of its rebuilds rather
+// than Fedora.
+func (f *FedoraTools) rhel() bool {
+	switch f.id {
+	case "rhel", "centos", "rocky", "almalinux":
+		return true
+	default:
+		return false
+	}
+}
+
+// rhelEPELPackages are the packages that RHEL and its rebuilds only carry
+// in EPEL.
+var rhelEPELPackages = []string{"pipx", "zoxide", "direnv", "neovim"}
+
+// epelReleaseURL is the EPEL release package for a RHEL major version.
+const epelReleaseURL = "https://dl.fedoraproject.org/pub/epel/epel-release-latest-%s.noarch.rpm"
+
+func needsEPEL(packages []string) bool {
+	for _, pkg := range packages {
+		if containsString(rhelEPELPackages, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enableEPEL enables EPEL, and CRB which many EPEL packages depend on.
+// The rebuilds carry epel-release themselves, RHEL gets it from EPEL.
+func (f *FedoraTools) enableEPEL() error {
+	installed, err := f.pm.IsInstalled("epel-release")
+	if err != nil {
+		return err
+	}
+
+	if !installed {
+		color.Blue("Enabling EPEL...")
+		if f.id == "rhel" {
+			release, err := exec.Command("rpm", "-E", "%rhel").Output()
+			if err != nil {
+				return fmt.Errorf("failed to determine the RHEL release: %w", err)
+			}
+			if err := f.runCommand("sudo dnf install -y", fmt.Sprintf(epelReleaseURL, strings.TrimSpace(string(release)))); err != nil {
+				return err
+			}
+		} else if err := f.pm.Install("epel-release"); err != nil {
+			return err
+		}
+	}
+
+	// epel-release ships a crb script from EL9 on
+	if _, err := exec.LookPath("crb"); err == nil {
+		return f.runCommand("sudo crb enable")
+	}
+
+	return nil
+}
+
+func (f *FedoraTools) dockerRepo() string {
+	switch {
+	case f.id == "centos":
+		return fmt.Sprintf(dockerRepoURL, "centos")
+	case f.rhel():
+		return fmt.Sprintf(dockerRepoURL, "rhel")
+	default:
+		return fmt.Sprintf(dockerRepoURL, "fedora")
+	}
+}
+
+func (t *FedoraTools) Run() error {
+	t.pm.Update()
+
+	batch := BatchPackages(t.tools, fedoraPackages)
+	if t.rhel() && needsEPEL(batch) {
+		if err := t.enableEPEL(); err != nil {
+			return err
+		}
+	}
+
+	if err := t.pm.Install(batch...); err != nil {
+		return err
+	}
+
+	for _, tool := range t.tools {
+		color.Blue("Setting up %s...", tool)
+		var err error
+
+		switch tool {
+		case "zsh":
+			err = t.InstallZsh()
+		case "go":
+			err = t.InstallGo()
+		case "make":
+			err = t.InstallMake()
+		case "gcc":
+			err = t.InstallGcc()
+		case "unzip":
+			err = t.InstallUnzip()
+		case "docker":
+			err = t.InstallDocker()
+		case "tmux":
+			err = t.InstallTmux()
+		case "node":
+			err = t.InstallNode()
+		case "python":
+			err = t.InstallPython()
+		case "neovim":
+			err = t.InstallNeovim()
+		case "poetry":
+			err = t.InstallPoetry()
+		case "bitwarden":
+			err = t.InstallBitwarden()
+		case "starship":
+			err = t.InstallStarship()
+		case "zoxide":
+			err = t.InstallZoxide()
+		case "direnv":
+			err = t.InstallDirenv()
+		case "rust":
+			err = t.InstallRust()
+		case "releases":
+			err = t.InstallReleases()
+		default:
+			err = InstallCustomTool(t.pm, "Fedora", tool, t.custom)
+		}
+
+		if err != nil {
+			color.Red("Error installing %s: %v", tool, err)
+			return err
+		}
+
+		color.Green("%s installed and configured successfully", tool)
+	}
+
+	return nil
+}
+
+func (f *FedoraTools) InstallNeovim() error {
+	color.Blue("Installing Neovim...")
+	if err := f.pm.Install("neovim"); err != nil {
+		return err
+	}
+
+	return f.ConfigureNeovim()
+}
+
+func (f *FedoraTools) InstallZsh() error {
+	color.Blue("Installing Zsh...")
+	if err := f.pm.Install("zsh"); err != nil {
+		return err
+	}
+
+	// Change default shell to Zsh
+	if err := SetLoginShell("zsh"); err != nil {
+		return err
+	}
+
+	// Install Oh My Zsh
+	return f.InstallOhMyZsh()
+}
+
+func (f *FedoraTools) InstallGcc() error {
+	color.Blue("Installing GCC...")
+	return f.installDevelopmentTools()
+}
+
+func (f *FedoraTools) InstallMake() error {
+	color.Blue("Installing Make...")
+	return f.installDevelopmentTools()
+}
+
+// installDevelopmentTools installs the group providing gcc and make once,
+// however many of them are selected.
+func (f *FedoraTools) installDevelopmentTools() error {
+	if f.developmentTools {
+		fmt.Println("Development Tools are already installed, skipping.")
+		return nil
+	}
+
+	group := fedoraDevelopmentTools
+	if f.rhel() {
+		group = rhelDevelopmentTools
+	}
+
+	if err := f.pm.InstallGroup(group); err != nil {
+		return err
+	}
+
+	f.developmentTools = true
+	return nil
+}
+
+func (f *FedoraTools) InstallUnzip() error {
+	color.Blue("Installing Unzip...")
+	return f.pm.Install("unzip")
+}
+
+func (f *FedoraTools) InstallOhMyZsh() error {
+	return SetupOhMyZsh()
+}
+
+func (f *FedoraTools) InstallDocker() error {
+	color.Blue("Installing Docker...")
+	if err := f.pm.Install("dnf-plugins-core"); err != nil {
+		return err
+	}
+
+	// dnf5 (Fedora 41+) changed the config-manager syntax, so fall back to the
+	// dnf4 form when the new one isn't understood.
+	if err := f.runCommand("sudo dnf config-manager addrepo --from-repofile=" + f.dockerRepo()); err != nil {
+		if err := f.runCommand("sudo dnf config-manager --add-repo", f.dockerRepo()); err != nil {
+			return err
+		}
+	}
+
+	if err := f.pm.Install("docker-ce", "docker-ce-cli", "containerd.io", "docker-buildx-plugin", "docker-compose-plugin"); err != nil {
+		return err
+	}
+
+	if err := f.runCommand("sudo systemctl enable --now docker"); err != nil {
+		return err
+	}
+
+	color.Blue("Running Docker post-installation configuration...")
+	if err := f.runCommand("sudo usermod -aG docker", os.Getenv("USER")); err != nil {
+		color.Red("Error running Docker post-installation configuration: %v", err)
+	}
+
+	return nil
+}
+
+func (f *FedoraTools) InstallTmux() error {
+	color.Blue("Installing Tmux...")
+	if err := f.pm.Install("tmux"); err != nil {
+		return err
+	}
+
+	if err := installTmuxSessionizer(); err != nil {
+		return err
+	}
+
+	return ConfigureTmux(f.pm)
+}
+
+func (f *FedoraTools) InstallGo() error {
+	color.Blue("Installing Go...")
+	if err := f.pm.Install("golang"); err != nil {
+		return err
+	}
+
+	return SetupGoTools()
+}
+
+func (f *FedoraTools) InstallNode() error {
+	color.Blue("Installing Node...")
+	return SetupNode()
+}
+
+func (f *FedoraTools) InstallPython() error {
+	color.Blue("Installing Python...")
+	if err := f.pm.Install(fedoraPackages["python"]...); err != nil {
+		return err
+	}
+
+	return SetupPython()
+}
+
+func (f *FedoraTools) ConfigureNeovim() error {
+	color.Blue("Configuring Neovim...")
+	return f.runCommand("git clone https://github.com/tedraykov/init.lua.git", filepath.Join(HomePath(), ".config", "nvim"))
+}
+
+func (f *FedoraTools) InstallPoetry() error {
+	color.Blue("Installing Poetry...")
+	if err := f.pm.Install(fedoraPackages["poetry"]...); err != nil {
+		return err
+	}
+
+	return SetupPoetry()
+}
+
+func (f *FedoraTools) InstallBitwarden() error {
+	fmt.Println("Installing Bitwarden...")
+	return InstallNpmPackages("@bitwarden/cli")
+}
+
+func (f *FedoraTools) InstallStarship() error {
+	color.Blue("Installing Starship...")
+	if err := InstallStarshipScript(); err != nil {
+		return err
+	}
+
+	return ConfigureStarship()
+}
+
+func (f *FedoraTools) InstallZoxide() error {
+	color.Blue("Installing Zoxide...")
+	if err := f.pm.Install(fedoraPackages["zoxide"]...); err != nil {
+		return err
+	}
+
+	return ConfigureZoxide()
+}
+
+func (f *FedoraTools) InstallDirenv() error {
+	color.Blue("Installing Direnv...")
+	if err := f.pm.Install(fedoraPackages["direnv"]...); err != nil {
+		return err
+	}
+
+	return ConfigureDirenv()
+}
+
+func (f *FedoraTools) InstallRust() error {
+	color.Blue("Installing Rust...")
+	return SetupRust()
+}
+
+func (f *FedoraTools) InstallReleases() error {
+	color.Blue("Installing GitHub releases...")
+	return SetupReleases()
+}
+
+func (f *FedoraTools) runCommand(args ...string) error {
+	return RunCommand(args...)
+}
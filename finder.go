@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bonuses for matching the last path element, which names the project.
+const (
+	fuzzyExactBaseBonus  = 100
+	fuzzyPrefixBaseBonus = 50
+)
+
+// FuzzyScore scores how well query matches candidate as a case-insensitive
+// subsequence. Consecutive characters and matches at word boundaries score
+// higher, and the best alignment counts rather than the leftmost one.
+// Matching the base name of a path, above all exactly or as a prefix, adds
+// to that. ok is false when query isn't a subsequence of candidate.
+func FuzzyScore(query, candidate string) (score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	c := []rune(strings.ToLower(candidate))
+
+	score, ok = alignScore(q, c)
+	if !ok {
+		return 0, false
+	}
+
+	base := []rune(strings.ToLower(filepath.Base(candidate)))
+	switch {
+	case string(base) == string(q):
+		score += fuzzyExactBaseBonus
+	case strings.HasPrefix(string(base), string(q)):
+		score += fuzzyPrefixBaseBonus
+	}
+	if baseScore, ok := alignScore(q, base); ok {
+		score += baseScore
+	}
+
+	// Prefer shorter candidates when the match quality is equal
+	return score*100 - len(c), true
+}
+
+// alignScore finds the best scoring way to match q as a subsequence of c.
+// best[j] holds the best score of the query so far ending at c[j].
+func alignScore(q, c []rune) (int, bool) {
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	const none = -1
+	best := make([]int, len(c))
+	for j := range c {
+		best[j] = none
+		if c[j] == q[0] {
+			best[j] = fuzzyCharScore(c, j)
+		}
+	}
+
+	for i := 1; i < len(q); i++ {
+		next := make([]int, len(c))
+		// bestBefore is the best score ending more than one rune before j
+		bestBefore := none
+		for j := range c {
+			next[j] = none
+			if j >= 2 && best[j-2] > bestBefore {
+				bestBefore = best[j-2]
+			}
+			if c[j] != q[i] {
+				continue
+			}
+
+			// A gap costs a little, a consecutive match earns a bonus
+			prev := none
+			if bestBefore != none {
+				prev = bestBefore - 1
+			}
+			if j >= 1 && best[j-1] != none && best[j-1]+4 > prev {
+				prev = best[j-1] + 4
+			}
+			if prev != none {
+				next[j] = prev + fuzzyCharScore(c, j)
+			}
+		}
+		best = next
+	}
+
+	score := none
+	for _, s := range best {
+		if s > score {
+			score = s
+		}
+	}
+
+	return score, score != none
+}
+
+// fuzzyCharScore scores matching c[j], with a bonus at a word boundary.
+func fuzzyCharScore(c []rune, j int) int {
+	if j == 0 || !unicode.IsLetter(c[j-1]) && !unicode.IsDigit(c[j-1]) {
+		return 5
+	}
+
+	return 2
+}
+
+// FuzzyFilter returns the candidates matching query, best match first.
+func FuzzyFilter(query string, candidates []string) []string {
+	if query == "" {
+		return candidates
+	}
+
+	type match struct {
+		candidate string
+		score     int
+	}
+
+	matches := []match{}
+	for _, candidate := range candidates {
+		if score, ok := FuzzyScore(query, candidate); ok {
+			matches = append(matches, match{candidate, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	filtered := make([]string, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.candidate
+	}
+
+	return filtered
+}
+
+// finderModel is a minimal fzf replacement: type to filter, move with the
+// arrow keys and press enter to pick.
+type finderModel struct {
+	candidates []string
+	matches    []string
+	query      string
+	cursor     int
+	height     int
+	selected   string
+}
+
+func newFinderModel(candidates []string) finderModel {
+	return finderModel{
+		candidates: candidates,
+		matches:    candidates,
+		height:     20,
+	}
+}
+
+func (m finderModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m finderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height - 3
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return m, tea.Quit
+		case tea.KeyEnter:
+			if len(m.matches) > 0 {
+				m.selected = m.matches[m.cursor]
+			}
+			return m, tea.Quit
+		case tea.KeyUp, tea.KeyCtrlP:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case tea.KeyDown, tea.KeyCtrlN:
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+		case tea.KeyBackspace:
+			if len(m.query) > 0 {
+				runes := []rune(m.query)
+				m.query = string(runes[:len(runes)-1])
+				m.filter()
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.query += string(msg.Runes)
+			m.filter()
+		}
+	}
+	return m, nil
+}
+
+func (m *finderModel) filter() {
+	m.matches = FuzzyFilter(m.query, m.candidates)
+	m.cursor = 0
+}
+
+func (m finderModel) View() string {
+	s := fmt.Sprintf("> %s\n", m.query)
+	s += fmt.Sprintf("  %d/%d\n", len(m.matches), len(m.candidates))
+
+	// Scroll so the cursor stays visible
+	start := 0
+	if m.height > 0 && m.cursor >= m.height {
+		start = m.cursor - m.height + 1
+	}
+
+	for i := start; i < len(m.matches) && (m.height <= 0 || i < start+m.height); i++ {
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %s\n", cursor, m.matches[i])
+	}
+
+	return s
+}
+
+// FuzzyFind lets the user pick one of candidates. It returns an empty string
+// when the finder is cancelled.
+func FuzzyFind(candidates []string) (string, error) {
+	m, err := tea.NewProgram(newFinderModel(candidates), tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", err
+	}
+
+	return m.(finderModel).selected, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:  "exact base name first",
+			query: "api",
+			candidates: []string{
+				"/home/u/dev/databyte/api",
+				"/home/u/dev/apis",
+				"/home/u/dev/a/p/i",
+			},
+			want: []string{
+				"/home/u/dev/databyte/api",
+				"/home/u/dev/apis",
+				"/home/u/dev/a/p/i",
+			},
+		},
+		{
+			name:  "base name prefix over a match in the parent",
+			query: "dot",
+			candidates: []string{
+				"/home/u/dotfiles-old/src",
+				"/home/u/dev/dotfiles",
+			},
+			want: []string{
+				"/home/u/dev/dotfiles",
+				"/home/u/dotfiles-old/src",
+			},
+		},
+		{
+			name:  "consecutive over scattered",
+			query: "tool",
+			candidates: []string{
+				"/home/u/dev/t-o-o-l",
+				"/home/u/dev/devtools",
+			},
+			want: []string{
+				"/home/u/dev/devtools",
+				"/home/u/dev/t-o-o-l",
+			},
+		},
+		{
+			name:       "case insensitive",
+			query:      "API",
+			candidates: []string{"/home/u/dev/api"},
+			want:       []string{"/home/u/dev/api"},
+		},
+		{
+			name:       "non-matching candidates are dropped",
+			query:      "xyz",
+			candidates: []string{"/home/u/dev/api", "/home/u/x/y/z"},
+			want:       []string{"/home/u/x/y/z"},
+		},
+		{
+			name:       "empty query keeps the order",
+			query:      "",
+			candidates: []string{"/b", "/a"},
+			want:       []string{"/b", "/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FuzzyFilter(tt.query, tt.candidates)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreFindsBestAlignment(t *testing.T) {
+	// The leftmost match scatters "ab" over a and b; the best one is the
+	// consecutive ab at the end
+	scattered, _ := FuzzyScore("ab", "/x/a-b")
+	aligned, ok := FuzzyScore("ab", "/x/a-b/ab")
+	if !ok {
+		t.Fatal("ab didn't match")
+	}
+	if aligned <= scattered {
+		t.Errorf("best alignment scored %d, no better than the scattered match %d", aligned, scattered)
+	}
+}
@@ -5,6 +5,7 @@ go 1.23
 require (
 	github.com/charmbracelet/bubbletea v0.27.0
 	github.com/fatih/color v1.17.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
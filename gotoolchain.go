@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const goUsage = "usage: devtools go install [version] | use [version] | list | clean | tools"
+
+// goDownloadURL serves the Go release archives and their index.
+var goDownloadURL = "https://go.dev/dl/"
+
+type goRelease struct {
+	Version string          `json:"version"`
+	Files   []goReleaseFile `json:"files"`
+}
+
+type goReleaseFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Kind     string `json:"kind"`
+	SHA256   string `json:"sha256"`
+}
+
+// GoVersionsPath holds one directory per installed Go version.
+func GoVersionsPath() string {
+	return filepath.Join(DataDir(), "go", "versions")
+}
+
+// GoCurrentPath is a symlink to the Go version in use; its bin directory is
+// the one on PATH.
+func GoCurrentPath() string {
+	return filepath.Join(DataDir(), "go", "current")
+}
+
+// GoToolchainCommand runs `devtools go`.
+func GoToolchainCommand(config *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(goUsage)
+	}
+
+	version := ""
+	if len(args) > 1 {
+		version = args[1]
+	}
+
+	switch args[0] {
+	case "install":
+		version, err := resolveGoVersion(version)
+		if err != nil {
+			return err
+		}
+		return InstallGoVersion(version)
+	case "use":
+		version, err := resolveGoVersion(version)
+		if err != nil {
+			return err
+		}
+		return UseGoVersion(version)
+	case "list":
+		return listGoVersions()
+	case "clean":
+		return CleanGoVersions()
+	case "tools":
+		return GoToolsCommand(config, args[1:])
+	default:
+		return errors.New(goUsage)
+	}
+}
+
+// resolveGoVersion normalizes version, falling back to the toolchain the
+// go.mod of the current directory asks for.
+func resolveGoVersion(version string) (string, error) {
+	if version != "" {
+		return NormalizeGoVersion(version), nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	version, err = GoModToolchain(dir)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fmt.Errorf("no version given and no go.mod found")
+	}
+
+	fmt.Printf("Using Go %s from go.mod\n", version)
+	return version, nil
+}
+
+// NormalizeGoVersion strips the go prefix, so go1.22.5 and 1.22.5 name the
+// same version.
+func NormalizeGoVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "go")
+}
+
+// GoModToolchain returns the Go version required by the go.mod governing
+// dir: its toolchain directive, or else its go directive. It returns an
+// empty string when there's no go.mod.
+func GoModToolchain(dir string) (string, error) {
+	for {
+		path := filepath.Join(dir, "go.mod")
+		file, err := os.Open(path)
+		if err == nil {
+			defer file.Close()
+			return parseGoModToolchain(file)
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func parseGoModToolchain(file io.Reader) (string, error) {
+	goVersion, toolchain := "", ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goVersion = fields[1]
+		case "toolchain":
+			// toolchain default means no toolchain beyond the go line
+			if fields[1] != "default" {
+				toolchain = NormalizeGoVersion(fields[1])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if toolchain != "" {
+		return toolchain, nil
+	}
+
+	// Since Go 1.21 a go line without a patch version means the .0 release
+	parts := strings.Split(goVersion, ".")
+	if len(parts) == 2 {
+		if minor, err := strconv.Atoi(parts[1]); err == nil && minor >= 21 {
+			return goVersion + ".0", nil
+		}
+	}
+
+	return goVersion, nil
+}
+
+func fetchGoReleases() ([]goRelease, error) {
+	resp, err := http.Get(goDownloadURL + "?mode=json&include=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list Go releases: %s", resp.Status)
+	}
+
+	releases := []goRelease{}
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse Go releases: %w", err)
+	}
+
+	return releases, nil
+}
+
+// InstallGoVersion downloads, verifies and unpacks a Go release into its
+// own directory under GoVersionsPath. Installed versions are skipped.
+func InstallGoVersion(version string) error {
+	dir := filepath.Join(GoVersionsPath(), version)
+	if _, err := os.Stat(dir); err == nil {
+		fmt.Printf("Go %s is already installed, skipping.\n", version)
+		return nil
+	}
+
+	releases, err := fetchGoReleases()
+	if err != nil {
+		return err
+	}
+
+	var archive *goReleaseFile
+	for _, release := range releases {
+		if release.Version != "go"+version {
+			continue
+		}
+		for i, file := range release.Files {
+			if file.OS == runtime.GOOS && file.Arch == runtime.GOARCH && file.Kind == "archive" {
+				archive = &release.Files[i]
+			}
+		}
+	}
+	if archive == nil {
+		return fmt.Errorf("no Go %s release for %s/%s", version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	if err := os.MkdirAll(GoVersionsPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", GoVersionsPath(), err)
+	}
+
+	tmp, err := os.MkdirTemp(GoVersionsPath(), ".install-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	color.Blue("Installing Go %s...", version)
+	tarball := filepath.Join(tmp, archive.Filename)
+	if err := DownloadFile(goDownloadURL+archive.Filename, tarball, archive.SHA256); err != nil {
+		return err
+	}
+
+	// Extract next to the final location so a failed install leaves nothing
+	// behind, then move it into place
+	extracted := filepath.Join(tmp, "go")
+	if err := ExtractTarGz(tarball, extracted, 1); err != nil {
+		return err
+	}
+
+	return os.Rename(extracted, dir)
+}
+
+// legacyGoPath is where devtools used to install Go.
+const legacyGoPath = "/usr/local/go/bin"
+
+// UseGoVersion points GoCurrentPath at version, installing it first if
+// needed. Its bin directory goes in front of PATH, so it also wins over Go
+// from the package manager on the OSes that install that.
+func UseGoVersion(version string) error {
+	if err := InstallGoVersion(version); err != nil {
+		return err
+	}
+
+	if err := ReplaceSymlink(filepath.Join(GoVersionsPath(), version), GoCurrentPath()); err != nil {
+		return fmt.Errorf("failed to switch to Go %s: %w", version, err)
+	}
+
+	if err := PrependToPath(filepath.Join(GoCurrentPath(), "bin"), legacyGoPath); err != nil {
+		return err
+	}
+
+	color.Green("Now using Go %s", version)
+	return nil
+}
+
+// CurrentGoVersion returns the version GoCurrentPath points at, or an empty
+// string when none is in use.
+func CurrentGoVersion() string {
+	target, err := os.Readlink(GoCurrentPath())
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+// InstalledGoVersions lists the installed Go versions, oldest first.
+func InstalledGoVersions() ([]string, error) {
+	entries, err := os.ReadDir(GoVersionsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Go versions: %w", err)
+	}
+
+	versions := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+
+	return versions, nil
+}
+
+func listGoVersions() error {
+	versions, err := InstalledGoVersions()
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("No Go versions installed.")
+		return nil
+	}
+
+	current := CurrentGoVersion()
+	required := ""
+	if dir, err := os.Getwd(); err == nil {
+		required, _ = GoModToolchain(dir)
+	}
+
+	for _, version := range versions {
+		marker := " "
+		if version == current {
+			marker = "*"
+		}
+
+		note := ""
+		if version == required {
+			note = " (go.mod)"
+		}
+
+		fmt.Printf("%s %s%s\n", marker, version, note)
+	}
+
+	return nil
+}
+
+// CleanGoVersions removes every installed version except the current one
+// and the one the go.mod of the current directory requires.
+func CleanGoVersions() error {
+	versions, err := InstalledGoVersions()
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{CurrentGoVersion(): true}
+	if dir, err := os.Getwd(); err == nil {
+		if required, err := GoModToolchain(dir); err == nil {
+			keep[required] = true
+		}
+	}
+
+	for _, version := range versions {
+		if keep[version] {
+			continue
+		}
+
+		color.Blue("Removing Go %s...", version)
+		if err := os.RemoveAll(filepath.Join(GoVersionsPath(), version)); err != nil {
+			return fmt.Errorf("failed to remove Go %s: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// CompareVersions compares dotted numeric versions like 1.22.5, returning
+// -1, 0 or 1. Non-numeric parts such as rc1 compare as zero.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGoModToolchain(t *testing.T) {
+	tests := []struct {
+		name  string
+		goMod string
+		want  string
+	}{
+		{"toolchain directive", "module m\n\ngo 1.22\n\ntoolchain go1.22.4\n", "1.22.4"},
+		{"go directive with patch", "module m\n\ngo 1.21.3\n", "1.21.3"},
+		{"go directive without patch", "module m\n\ngo 1.22\n", "1.22.0"},
+		{"go directive before 1.21", "module m\n\ngo 1.20\n", "1.20"},
+		{"toolchain default", "module m\n\ngo 1.23.1\n\ntoolchain default\n", "1.23.1"},
+		{"comment after the version", "module m\n\ngo 1.22\ntoolchain go1.22.2 // pinned\n", "1.22.2"},
+		{"no directives", "module m\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGoModToolchain(strings.NewReader(tt.goMod))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseGoModToolchain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.22.1", "1.22.1", 0},
+		{"1.22.0", "1.22.1", -1},
+		{"1.22.10", "1.22.9", 1},
+		{"1.9", "1.10", -1},
+		{"1.21", "1.21.0", 0},
+		{"1.21.1", "1.21", 1},
+		{"2.0.0", "1.99.99", 1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+const goToolsUsage = "usage: devtools go tools install | upgrade | list"
+
+// GoSettings configures the Go developer tools installed with Go.
+type GoSettings struct {
+	// Tools are installed with go install, e.g. golang.org/x/tools/gopls@latest
+	Tools []GoTool `yaml:"tools"`
+	// Bin is where the tools are installed; go env GOBIN when empty
+	Bin string `yaml:"bin"`
+	// Proxy overrides GOPROXY for the installs when set
+	Proxy string `yaml:"proxy"`
+}
+
+type GoTool struct {
+	// Package is the import path of the tool's main package
+	Package string `yaml:"package"`
+	// Version is a module version or query, latest when empty
+	Version string `yaml:"version"`
+}
+
+// UnmarshalYAML also accepts a tool written as package@version.
+func (t *GoTool) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = ParseGoTool(node.Value)
+		return nil
+	}
+
+	type plain GoTool
+	if err := node.Decode((*plain)(t)); err != nil {
+		return err
+	}
+
+	if t.Version == "" {
+		t.Version = "latest"
+	}
+
+	return nil
+}
+
+// ParseGoTool parses package@version. The version defaults to latest.
+func ParseGoTool(spec string) GoTool {
+	pkg, version, ok := strings.Cut(spec, "@")
+	if !ok || version == "" {
+		version = "latest"
+	}
+
+	return GoTool{Package: pkg, Version: version}
+}
+
+func (t GoTool) String() string {
+	return t.Package + "@" + t.Version
+}
+
+var goMajorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// Binary returns the name go install gives the tool's executable: the last
+// element of the package path, skipping a major version suffix.
+func (t GoTool) Binary() string {
+	name := path.Base(t.Package)
+	if goMajorVersionPattern.MatchString(name) {
+		name = path.Base(path.Dir(t.Package))
+	}
+
+	return name
+}
+
+func DefaultGoSettings() GoSettings {
+	return GoSettings{
+		Tools: []GoTool{
+			ParseGoTool("golang.org/x/tools/gopls@latest"),
+			ParseGoTool("github.com/go-delve/delve/cmd/dlv@latest"),
+			ParseGoTool("github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest"),
+			ParseGoTool("honnef.co/go/tools/cmd/staticcheck@latest"),
+			ParseGoTool("golang.org/x/tools/cmd/goimports@latest"),
+		},
+	}
+}
+
+// goToolState is what devtools recorded about an installed tool.
+type goToolState struct {
+	// Version is the requested version, e.g. latest
+	Version string `json:"version"`
+	// Installed is the module version that was resolved and built
+	Installed string `json:"installed"`
+}
+
+func goToolStatePath() string {
+	return filepath.Join(StateDir(), "go-tools.json")
+}
+
+// goCommand returns the go binary to install tools with, preferring the
+// version devtools manages over the one on PATH.
+func goCommand() string {
+	managed := filepath.Join(GoCurrentPath(), "bin", "go")
+	if _, err := os.Stat(managed); err == nil {
+		return managed
+	}
+
+	return "go"
+}
+
+// GoBinPath returns the directory the Go tools are installed into.
+func GoBinPath(settings GoSettings) (string, error) {
+	if settings.Bin != "" {
+		return ExpandHome(settings.Bin), nil
+	}
+
+	for _, name := range []string{"GOBIN", "GOPATH"} {
+		out, err := exec.Command(goCommand(), "env", name).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run go env: %w", err)
+		}
+
+		dir := strings.TrimSpace(string(out))
+		if dir == "" {
+			continue
+		}
+		if name == "GOPATH" {
+			// GOPATH may list several directories; go install uses the first
+			dir = filepath.Join(filepath.SplitList(dir)[0], "bin")
+		}
+		return dir, nil
+	}
+
+	return "", errors.New("failed to determine the Go bin directory")
+}
+
+// GoToolsCommand runs `devtools go tools`.
+func GoToolsCommand(config *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(goToolsUsage)
+	}
+
+	switch args[0] {
+	case "install":
+		return InstallGoTools(config.Go, false)
+	case "upgrade":
+		return InstallGoTools(config.Go, true)
+	case "list":
+		return listGoTools(config.Go)
+	default:
+		return errors.New(goToolsUsage)
+	}
+}
+
+// SetupGoTools installs the Go tools from the config, once Go itself is
+// installed.
+func SetupGoTools() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	return InstallGoTools(config.Go, false)
+}
+
+// InstallGoTools installs the configured tools into GoBinPath and records
+// the versions that were built. Tools that are installed at the requested
+// version are skipped unless upgrade is set, which resolves versions like
+// latest again.
+func InstallGoTools(settings GoSettings, upgrade bool) error {
+	bin, err := GoBinPath(settings)
+	if err != nil {
+		return err
+	}
+
+	state, err := loadGoToolState()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", bin, err)
+	}
+
+	for _, tool := range settings.Tools {
+		binary := filepath.Join(bin, tool.Binary())
+
+		previous, tracked := state[tool.Package]
+		if _, err := os.Stat(binary); err == nil && tracked && previous.Version == tool.Version && !upgrade {
+			fmt.Printf("%s is already installed, skipping.\n", tool)
+			continue
+		}
+
+		color.Blue("Installing %s...", tool)
+		if err := installGoTool(settings, bin, tool); err != nil {
+			return err
+		}
+
+		installed, err := goBinaryVersion(binary)
+		if err != nil {
+			return err
+		}
+
+		if tracked && previous.Installed != installed {
+			color.Green("Upgraded %s from %s to %s", tool.Binary(), previous.Installed, installed)
+		}
+
+		state[tool.Package] = goToolState{Version: tool.Version, Installed: installed}
+		if err := saveGoToolState(state); err != nil {
+			return err
+		}
+	}
+
+	return AddToRCFiles("export PATH=$PATH:" + bin)
+}
+
+func installGoTool(settings GoSettings, bin string, tool GoTool) error {
+	cmd := exec.Command(goCommand(), "install", tool.String())
+	cmd.Env = append(os.Environ(), "GOBIN="+bin)
+	if settings.Proxy != "" {
+		cmd.Env = append(cmd.Env, "GOPROXY="+settings.Proxy)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	fmt.Printf("Running command: go install %s\n", tool)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to install %s: %w", tool, err)
+	}
+
+	return nil
+}
+
+// goBinaryVersion reads the version of the main module a Go binary was
+// built from.
+func goBinaryVersion(binary string) (string, error) {
+	out, err := exec.Command(goCommand(), "version", "-m", binary).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the version of %s: %w", binary, err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "mod" {
+			return fields[2], nil
+		}
+	}
+
+	return "", fmt.Errorf("no module version in %s", binary)
+}
+
+func listGoTools(settings GoSettings) error {
+	state, err := loadGoToolState()
+	if err != nil {
+		return err
+	}
+
+	for _, tool := range settings.Tools {
+		installed := "not installed"
+		if entry, ok := state[tool.Package]; ok {
+			installed = entry.Installed
+		}
+
+		fmt.Printf("%s %s (%s)\n", tool.Binary(), installed, tool)
+	}
+
+	return nil
+}
+
+func loadGoToolState() (map[string]goToolState, error) {
+	state := map[string]goToolState{}
+
+	content, err := os.ReadFile(goToolStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Go tool state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", goToolStatePath(), err)
+	}
+
+	return state, nil
+}
+
+func saveGoToolState(state map[string]goToolState) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Go tool state: %w", err)
+	}
+
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return WriteContentToFile(string(content), goToolStatePath())
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeGoProxyModule publishes a module with a main package to a GOPROXY
+// directory.
+func writeGoProxyModule(t *testing.T, proxy, module, version string) {
+	t.Helper()
+
+	dir := filepath.Join(proxy, module, "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	goMod := "module " + module + "\n\ngo 1.21\n"
+	files := map[string]string{
+		"list":            version + "\n",
+		version + ".info": `{"Version":"` + version + `"}`,
+		version + ".mod":  goMod,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive, err := os.Create(filepath.Join(dir, version+".zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	w := zip.NewWriter(archive)
+	prefix := module + "@" + version + "/"
+	sources := map[string]string{
+		"go.mod":  goMod,
+		"main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range sources {
+		f, err := w.Create(prefix + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallGoToolsFromProxy(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+
+	// Keep the build cache of the real home so the builds stay fast
+	cache, err := exec.Command("go", "env", "GOCACHE").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := t.TempDir()
+	proxy := t.TempDir()
+	modcache := t.TempDir()
+	// The module cache is read-only, which the temp dir cleanup can't remove
+	t.Cleanup(func() { exec.Command("chmod", "-R", "u+w", modcache).Run() })
+
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("GOCACHE", strings.TrimSpace(string(cache)))
+	t.Setenv("GOMODCACHE", modcache)
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOTOOLCHAIN", "local")
+
+	writeGoProxyModule(t, proxy, "example.com/hello", "v1.0.0")
+	writeGoProxyModule(t, proxy, "example.com/bye", "v1.2.0")
+
+	content := `
+tools:
+  - example.com/hello@v1.0.0
+  - package: example.com/bye
+bin: ~/bin
+proxy: file://` + filepath.ToSlash(proxy) + `
+`
+	var settings GoSettings
+	if err := yaml.Unmarshal([]byte(content), &settings); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []GoTool{
+		{Package: "example.com/hello", Version: "v1.0.0"},
+		{Package: "example.com/bye", Version: "latest"},
+	}
+	for i, tool := range want {
+		if settings.Tools[i] != tool {
+			t.Errorf("tool %d is %+v, want %+v", i, settings.Tools[i], tool)
+		}
+	}
+
+	if err := InstallGoTools(settings, false); err != nil {
+		t.Fatalf("InstallGoTools failed: %v", err)
+	}
+
+	for _, binary := range []string{"hello", "bye"} {
+		if _, err := os.Stat(filepath.Join(home, "bin", binary)); err != nil {
+			t.Errorf("%s wasn't installed: %v", binary, err)
+		}
+	}
+
+	state, err := os.ReadFile(goToolStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := map[string]goToolState{}
+	if err := json.Unmarshal(state, &recorded); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorded["example.com/bye"]; got.Version != "latest" || got.Installed != "v1.2.0" {
+		t.Errorf("example.com/bye recorded as %+v, want latest resolved to v1.2.0", got)
+	}
+}
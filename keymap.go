@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keymap is a set of directional keys shared by the TUI and the generated
+// tmux bindings. Keys use tmux names, e.g. h or Left.
+type Keymap struct {
+	Left  string
+	Down  string
+	Up    string
+	Right string
+}
+
+var KeymapPresets = map[string]Keymap{
+	// vim is the standard hjkl layout and the default
+	"vim": {Left: "h", Down: "j", Up: "k", Right: "l"},
+	// shifted moves the vim keys one to the right, onto the home row. It
+	// takes over ; and l, so it's opt-in
+	"shifted": {Left: "j", Down: "k", Up: "l", Right: ";"},
+	// arrows only binds the arrow keys
+	"arrows": {Left: "Left", Down: "Down", Up: "Up", Right: "Right"},
+}
+
+const defaultKeymap = "vim"
+
+// LookupKeymap returns the preset called name.
+func LookupKeymap(name string) (Keymap, error) {
+	keymap, ok := KeymapPresets[name]
+	if !ok {
+		names := []string{}
+		for name := range KeymapPresets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return Keymap{}, fmt.Errorf("unknown keymap %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	return keymap, nil
+}
+
+func (k Keymap) PaneKeys() PaneKeys {
+	return PaneKeys{Left: k.Left, Down: k.Down, Up: k.Up, Right: k.Right}
+}
+
+// Action translates a bubbletea key to the direction it moves in, so the
+// TUI only has to handle "up" and "down". The arrow keys always work. Other
+// keys are returned as they are.
+func (k Keymap) Action(key string) string {
+	switch key {
+	case strings.ToLower(k.Up):
+		return "up"
+	case strings.ToLower(k.Down):
+		return "down"
+	case strings.ToLower(k.Left):
+		return "left"
+	case strings.ToLower(k.Right):
+		return "right"
+	default:
+		return key
+	}
+}
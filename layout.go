@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SessionLayoutFiles are the names a project's layout file is looked up by.
+var SessionLayoutFiles = []string{".devtools-session.yaml", ".devtools-session.yml"}
+
+// SessionLayout describes the windows a project's tmux session starts with.
+type SessionLayout struct {
+	Windows []WindowLayout `yaml:"windows"`
+}
+
+type WindowLayout struct {
+	Name string `yaml:"name"`
+	// Dir is the working directory, relative to the project
+	Dir     string `yaml:"dir"`
+	Command string `yaml:"command"`
+	// Layout is a tmux layout applied once the panes exist, e.g. main-vertical
+	Layout string       `yaml:"layout"`
+	Panes  []PaneLayout `yaml:"panes"`
+}
+
+// PaneLayout is a pane split off a window in addition to its first pane.
+type PaneLayout struct {
+	Dir     string `yaml:"dir"`
+	Command string `yaml:"command"`
+	// Split is "horizontal" for side by side panes or "vertical" (the
+	// default) for stacked ones
+	Split string `yaml:"split"`
+}
+
+// LoadSessionLayout reads the layout file of the project in dir. It returns
+// nil when the project has none.
+func LoadSessionLayout(dir string) (*SessionLayout, error) {
+	for _, name := range SessionLayoutFiles {
+		path := filepath.Join(dir, name)
+
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		layout := &SessionLayout{}
+		if err := yaml.Unmarshal(content, layout); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		return layout, nil
+	}
+
+	return nil, nil
+}
+
+// CreateSession creates a detached session named name for the project in
+// dir, applying the project's layout file if it has one.
+func CreateSession(name, dir string) error {
+	layout, err := LoadSessionLayout(dir)
+	if err != nil {
+		return err
+	}
+
+	if layout == nil || len(layout.Windows) == 0 {
+		return runTmux("new-session", "-d", "-s", name, "-c", dir)
+	}
+
+	for i, window := range layout.Windows {
+		args := []string{"new-window", "-t", name + ":"}
+		if i == 0 {
+			args = []string{"new-session", "-d", "-s", name}
+		}
+
+		args = append(args, "-P", "-F", "#{window_id}", "-c", layoutDir(dir, window.Dir))
+		if window.Name != "" {
+			args = append(args, "-n", window.Name)
+		}
+
+		windowID, err := tmuxOutput(args...)
+		if err != nil {
+			return err
+		}
+
+		if err := sendCommand(windowID, window.Command); err != nil {
+			return err
+		}
+
+		for _, pane := range window.Panes {
+			split := "-v"
+			if pane.Split == "horizontal" {
+				split = "-h"
+			}
+
+			// Panes start in their window's directory unless they set their own
+			paneDir := pane.Dir
+			if paneDir == "" {
+				paneDir = window.Dir
+			}
+
+			paneID, err := tmuxOutput("split-window", split, "-t", windowID, "-P", "-F", "#{pane_id}", "-c", layoutDir(dir, paneDir))
+			if err != nil {
+				return err
+			}
+
+			if err := sendCommand(paneID, pane.Command); err != nil {
+				return err
+			}
+		}
+
+		if window.Layout != "" {
+			if err := runTmux("select-layout", "-t", windowID, window.Layout); err != nil {
+				return err
+			}
+		}
+	}
+
+	return runTmux("select-window", "-t", name+":^")
+}
+
+// layoutDir resolves a layout working directory against the project dir.
+func layoutDir(project, dir string) string {
+	dir = ExpandHome(dir)
+	if dir == "" || !filepath.IsAbs(dir) {
+		return filepath.Join(project, dir)
+	}
+
+	return dir
+}
+
+func sendCommand(target, command string) error {
+	if command == "" {
+		return nil
+	}
+
+	return runTmux("send-keys", "-t", target, command, "Enter")
+}
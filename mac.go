@@ -4,18 +4,35 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
-	. "github.com/tedraykov/devtools/scripts"
 )
 
+// macPackages maps tools to the Homebrew formulae that can be installed
+// together in a single batch before the per-tool setup runs.
+var macPackages = map[string][]string{
+	"zsh":      {"zsh"},
+	"go":       {"go"},
+	"make":     {"make"},
+	"gcc":      {"gcc"},
+	"unzip":    {"unzip"},
+	"tmux":     {"tmux"},
+	"python":   {"python", "pipx"},
+	"poetry":   {"pipx"},
+	"neovim":   {"neovim"},
+	"starship": {"starship"},
+	"zoxide":   {"zoxide"},
+	"direnv":   {"direnv"},
+}
+
 type MacOsTools struct {
 	tools []string
+	pm    PackageManager
+	// custom are the custom tools that can be selected
+	custom []CustomTool
 }
 
 func (t *MacOsTools) Run() error {
@@ -24,7 +41,11 @@ func (t *MacOsTools) Run() error {
 		return err
 	}
 
-	t.runCommand("brew", "update")
+	t.pm.Update()
+
+	if err := t.pm.Install(BatchPackages(t.tools, macPackages)...); err != nil {
+		return err
+	}
 
 	for _, tool := range t.tools {
 		color.Blue("Setting up %s...", tool)
@@ -55,8 +76,18 @@ func (t *MacOsTools) Run() error {
       err = t.InstallPoetry()
     case "bitwarden":
       err = t.InstallBitwarden()
+    case "starship":
+      err = t.InstallStarship()
+    case "zoxide":
+      err = t.InstallZoxide()
+    case "direnv":
+      err = t.InstallDirenv()
+    case "rust":
+      err = t.InstallRust()
+    case "releases":
+      err = t.InstallReleases()
 		default:
-			color.Red("Error: %s is not a valid tool", tool)
+			err = InstallCustomTool(t.pm, "MacOS", tool, t.custom)
 		}
 
 		if err != nil {
@@ -82,7 +113,7 @@ func (m *MacOsTools) ensureHomebrew() error {
 
 func (m *MacOsTools) InstallNeovim() error {
 	color.Blue("Installing Neovim...")
-	if err := m.runCommand("brew", "install", "neovim"); err != nil {
+	if err := m.pm.Install("neovim"); err != nil {
 		return err
 	}
 
@@ -91,12 +122,12 @@ func (m *MacOsTools) InstallNeovim() error {
 
 func (m *MacOsTools) InstallZsh() error {
 	color.Blue("Installing Zsh...")
-	if err := m.runCommand("brew", "install", "zsh"); err != nil {
+	if err := m.pm.Install("zsh"); err != nil {
 		return err
 	}
 
 	// Change default shell to Zsh
-	if err := m.runCommand("chsh", "-s", "/bin/zsh"); err != nil {
+	if err := SetLoginShell("zsh"); err != nil {
 		return err
 	}
 
@@ -106,27 +137,26 @@ func (m *MacOsTools) InstallZsh() error {
 
 func (m *MacOsTools) InstallGcc() error {
 	color.Blue("Installing GCC...")
-	return m.runCommand("brew", "install", "gcc")
+	return m.pm.Install("gcc")
 }
 
 func (m *MacOsTools) InstallMake() error {
 	color.Blue("Installing Make...")
-	return m.runCommand("brew", "install", "make")
+	return m.pm.Install("make")
 }
 
 func (m *MacOsTools) InstallRipgrep() error {
 	color.Blue("Installing Ripgrep...")
-	return m.runCommand("brew", "install", "ripgrep")
+	return m.pm.Install("ripgrep")
 }
 
 func (m *MacOsTools) InstallUnzip() error {
 	color.Blue("Installing Unzip...")
-	return m.runCommand("brew", "install", "unzip")
+	return m.pm.Install("unzip")
 }
 
 func (m *MacOsTools) InstallOhMyZsh() error {
-	color.Blue("Installing Oh My Zsh...")
-	return m.runCommand("curl -fsSL https://raw.github.com/ohmyzsh/ohmyzsh/master/tools/install.sh")
+	return SetupOhMyZsh()
 }
 
 func (m *MacOsTools) InstallDocker() error {
@@ -136,29 +166,15 @@ func (m *MacOsTools) InstallDocker() error {
 
 func (m *MacOsTools) InstallTmux() error {
 	color.Blue("Installing Tmux...")
-	if err := m.runCommand("brew", "install", "tmux"); err != nil {
-		return err
-	}
-
-	color.Blue("Installing fzf...")
-	if err := m.runCommand("brew", "install", "fzf"); err != nil {
-		return err
-	}
-
-	tmuxSessionizerScriptPath := filepath.Join(LocalBinPath(), "tmux-sessionizer")
-	tmuxConfigPath := filepath.Join(HomePath(), ".tmux.conf")
-
-	color.Blue("Installing tmux-sessionizer script...")
-	if err := WriteContentToFile(TmuxSessionizer, tmuxSessionizerScriptPath); err != nil {
+	if err := m.pm.Install("tmux"); err != nil {
 		return err
 	}
 
-	if err := MakeExecutable(tmuxSessionizerScriptPath); err != nil {
+	if err := installTmuxSessionizer(); err != nil {
 		return err
 	}
 
-	color.Blue("Configuring tmux...")
-	return WriteContentToFile(TmuxConfig, tmuxConfigPath)
+	return ConfigureTmux(m.pm)
 }
 
 func (m *MacOsTools) getLastestGoVersion() (string, error) {
@@ -186,22 +202,25 @@ func (m *MacOsTools) getLastestGoVersion() (string, error) {
 
 func (m *MacOsTools) InstallGo() error {
 	color.Blue("Installing Go...")
-	return m.runCommand("brew", "install", "go")
+	if err := m.pm.Install("go"); err != nil {
+		return err
+	}
+
+	return SetupGoTools()
 }
 
 func (m *MacOsTools) InstallNode() error {
-	color.Blue("Installing NVM...")
-	m.runCommand("brew", "install", "nvm")
-
-  m.runCommand("source ~/.zshrc")
-  m.runCommand("nvm install --lts")
-
-  return nil
+	color.Blue("Installing Node...")
+	return SetupNode()
 }
 
 func (m *MacOsTools) InstallPython() error {
 	color.Blue("Installing Python...")
-	return m.runCommand("brew", "install", "python")
+	if err := m.pm.Install(macPackages["python"]...); err != nil {
+		return err
+	}
+
+	return SetupPython()
 }
 
 func (m *MacOsTools) ConfigureNeovim() error {
@@ -211,24 +230,55 @@ func (m *MacOsTools) ConfigureNeovim() error {
 
 func (m *MacOsTools) InstallPoetry() error {
   color.Blue("Installing Poetry...")
-  return m.runCommand("curl -sSL https://install.python-poetry.org | python3 -")
+  if err := m.pm.Install(macPackages["poetry"]...); err != nil {
+    return err
+  }
+
+  return SetupPoetry()
 }
 
 func (m *MacOsTools) InstallBitwarden() error {
     fmt.Println("Installing Bitwarden...")
-    return m.runCommand("npm install -g @bitwarden/cli")
+    return InstallNpmPackages("@bitwarden/cli")
 }
 
-func (m *MacOsTools) runCommand(args ...string) error {
-	splitArgs := []string{}
+func (m *MacOsTools) InstallStarship() error {
+	color.Blue("Installing Starship...")
+	if err := m.pm.Install(macPackages["starship"]...); err != nil {
+		return err
+	}
+
+	return ConfigureStarship()
+}
 
-	for _, arg := range args {
-		splitArgs = append(splitArgs, strings.Split(arg, " ")...)
+func (m *MacOsTools) InstallZoxide() error {
+	color.Blue("Installing Zoxide...")
+	if err := m.pm.Install(macPackages["zoxide"]...); err != nil {
+		return err
 	}
 
-	fmt.Printf("Running command: %s\n", strings.Join(splitArgs, " "))
-	cmd := exec.Command(splitArgs[0], splitArgs[1:]...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return ConfigureZoxide()
+}
+
+func (m *MacOsTools) InstallDirenv() error {
+	color.Blue("Installing Direnv...")
+	if err := m.pm.Install(macPackages["direnv"]...); err != nil {
+		return err
+	}
+
+	return ConfigureDirenv()
+}
+
+func (m *MacOsTools) InstallRust() error {
+	color.Blue("Installing Rust...")
+	return SetupRust()
+}
+
+func (m *MacOsTools) InstallReleases() error {
+	color.Blue("Installing GitHub releases...")
+	return SetupReleases()
+}
+
+func (m *MacOsTools) runCommand(args ...string) error {
+	return RunCommand(args...)
 }
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fatih/color"
 )
 
 type Tools interface {
@@ -20,11 +22,48 @@ type Tools interface {
   InstallPoetry() error
   InstallNeovim() error
   InstallBitwarden() error
+  InstallStarship() error
+  InstallZoxide() error
+  InstallDirenv() error
+  InstallRust() error
+  InstallReleases() error
 }
 
 type item struct {
-	title    string
-	selected bool
+	title       string
+	description string
+	selected    bool
+}
+
+// builtinTools are the tools devtools knows how to install, with whether
+// they're selected by default.
+var builtinTools = []item{
+	{title: "zsh", selected: true},
+	{title: "make", selected: true},
+	{title: "gcc", selected: true},
+	{title: "unzip", selected: true},
+	{title: "docker", selected: true},
+	{title: "tmux", selected: true},
+	{title: "go", selected: true},
+	{title: "node", selected: true},
+	{title: "python", selected: true},
+	{title: "poetry", selected: true},
+	{title: "neovim", selected: true},
+	{title: "bitwarden", selected: true},
+	{title: "starship", selected: false},
+	{title: "zoxide", selected: false},
+	{title: "direnv", selected: false},
+	{title: "rust", selected: false},
+	{title: "releases", selected: false},
+}
+
+func builtinToolNames() []string {
+	names := []string{}
+	for _, tool := range builtinTools {
+		names = append(names, tool.title)
+	}
+
+	return names
 }
 
 type viewState int
@@ -41,27 +80,33 @@ type model struct {
 	osSelected string
 	tools      []item
 	toolCursor int
+	keymap     Keymap
 }
 
-func initialModel() model {
+func initialModel(keymap Keymap, custom []CustomTool) model {
+	osChoices := []string{"Ubuntu", "Fedora", "Arch", "Alpine", "MacOS"}
+
+	// Start the cursor on the detected OS so enter just confirms it
+	osCursor := 0
+	detected := DetectOS()
+	for i, choice := range osChoices {
+		if choice == detected {
+			osCursor = i
+		}
+	}
+
+	tools := []item{{title: "All tools selected", selected: true}}
+	tools = append(tools, builtinTools...)
+	for _, tool := range custom {
+		tools = append(tools, item{title: tool.Name, description: tool.Description})
+	}
+
 	return model{
 		state:     osSelection,
-		osChoices: []string{"Ubuntu", "MacOS"},
-		tools: []item{
-			{title: "All tools selected", selected: true},
-			{title: "zsh", selected: true},
-			{title: "make", selected: true},
-			{title: "gcc", selected: true},
-			{title: "unzip", selected: true},
-			{title: "docker", selected: true},
-      {title: "tmux", selected: true},
-      {title: "go", selected: true},
-      {title: "node", selected: true},
-      {title: "python", selected: true},
-      {title: "poetry", selected: true},
-      {title: "neovim", selected: true},
-      {title: "bitwarden", selected: true},
-		},
+		osChoices: osChoices,
+		osCursor:  osCursor,
+		keymap:    keymap,
+		tools:     tools,
 	}
 }
 
@@ -74,14 +119,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch m.state {
 		case osSelection:
-			switch msg.String() {
+			switch m.keymap.Action(msg.String()) {
 			case "ctrl+c", "q":
 				return m, tea.Quit
-			case "up", "l":
+			case "up":
 				if m.osCursor > 0 {
 					m.osCursor--
 				}
-			case "down", "k":
+			case "down":
 				if m.osCursor < len(m.osChoices)-1 {
 					m.osCursor++
 				}
@@ -90,14 +135,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = toolSelection
 			}
 		case toolSelection:
-			switch msg.String() {
+			switch m.keymap.Action(msg.String()) {
 			case "ctrl+c", "q":
 				return m, tea.Quit
-			case "up", "l":
+			case "up":
 				if m.toolCursor > 0 {
 					m.toolCursor--
 				}
-			case "down", "k":
+			case "down":
 				if m.toolCursor < len(m.tools)-1 {
 					m.toolCursor++
 				}
@@ -173,7 +218,11 @@ func (m model) View() string {
 			} else if item.selected {
 				checked = "x"
 			}
-			s += fmt.Sprintf("%s [%s] %s\n", cursor, checked, item.title)
+			if item.description != "" {
+				s += fmt.Sprintf("%s [%s] %s - %s\n", cursor, checked, item.title, item.description)
+			} else {
+				s += fmt.Sprintf("%s [%s] %s\n", cursor, checked, item.title)
+			}
 		}
 		s += "\nPress space to select/unselect, up/down to move, enter to submit\n"
 		return s
@@ -183,7 +232,33 @@ func (m model) View() string {
 }
 
 func main() {
-	p := tea.NewProgram(initialModel())
+	if len(os.Args) > 1 {
+		if err := runSubcommand(os.Args[1], os.Args[2:]); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	keymap, err := config.KeymapPreset()
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	custom := CustomTools(config, builtinToolNames())
+	if err := ValidateCustomTools(custom, builtinToolNames()); err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(initialModel(keymap, custom))
 	m, err := p.Run()
 	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
@@ -204,14 +279,26 @@ func main() {
       return
 		}
 
-    var tools Tools
+		// Custom tools may pull in tools that weren't selected
+		selected, err = ResolveToolOrder(selected, custom)
+		if err != nil {
+			color.Red("Error: %v", err)
+			return
+		}
 
-    if model.osSelected == "Ubuntu" {
-      tools = &UbuntuTools{tools: selected}
-    }
+    var tools Tools
 
-    if model.osSelected == "MacOS" {
-       tools = &MacOsTools{tools: selected}
+    switch model.osSelected {
+    case "Ubuntu":
+      tools = &UbuntuTools{tools: selected, pm: &Apt{}, wsl: IsWSL(), custom: custom}
+    case "Fedora":
+      tools = &FedoraTools{tools: selected, pm: &Dnf{}, id: OSReleaseID(), custom: custom}
+    case "Arch":
+      tools = &ArchTools{tools: selected, pm: &Pacman{}, custom: custom}
+    case "Alpine":
+      tools = &AlpineTools{tools: selected, pm: &Apk{}, custom: custom}
+    case "MacOS":
+      tools = &MacOsTools{tools: selected, pm: &Brew{}, custom: custom}
     }
 
     if tools == nil {
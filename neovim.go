@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const neovimRepo = "neovim/neovim"
+
+// NeovimSettings configures the Neovim release installed from GitHub.
+type NeovimSettings struct {
+	// Version is latest, stable, nightly or a release tag like v0.10.4
+	Version string `yaml:"version"`
+	// Prefix holds one release tree per version; DataDir/neovim when empty
+	Prefix string `yaml:"prefix"`
+	// Aliases are other names Neovim runs as, e.g. vim or vi. nvim itself
+	// is always linked into LocalBinPath
+	Aliases []string `yaml:"aliases"`
+	// Alternatives registers the aliases system wide with update-alternatives
+	// instead of linking them into LocalBinPath. Since every user would run
+	// this Neovim, it needs a Prefix outside of the home directory
+	Alternatives bool `yaml:"alternatives"`
+}
+
+func DefaultNeovimSettings() NeovimSettings {
+	return NeovimSettings{
+		Version: "latest",
+		Aliases: []string{"vim"},
+	}
+}
+
+func (s NeovimSettings) prefix() string {
+	if s.Prefix != "" {
+		return ExpandHome(s.Prefix)
+	}
+
+	return filepath.Join(DataDir(), "neovim")
+}
+
+// current is a symlink to the release tree in use.
+func (s NeovimSettings) current() string {
+	return filepath.Join(s.prefix(), "current")
+}
+
+// neovimAssets lists the names the Neovim tarball for this platform went
+// by, newest first: 0.10.4 renamed nvim-linux64 and 0.10 split the macOS
+// build by arch.
+func neovimAssets() ([]string, error) {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return []string{"nvim-linux-x86_64.tar.gz", "nvim-linux64.tar.gz"}, nil
+	case "linux/arm64":
+		return []string{"nvim-linux-arm64.tar.gz"}, nil
+	case "darwin/amd64":
+		return []string{"nvim-macos-x86_64.tar.gz", "nvim-macos.tar.gz"}, nil
+	case "darwin/arm64":
+		return []string{"nvim-macos-arm64.tar.gz", "nvim-macos.tar.gz"}, nil
+	default:
+		return nil, fmt.Errorf("no Neovim release for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+}
+
+// SetupNeovim installs the configured Neovim release.
+func SetupNeovim() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	return InstallNeovimRelease(config.Neovim)
+}
+
+// InstallNeovimRelease installs the whole tree of a Neovim release, so the
+// runtime files stay next to the binary, switches to it and links nvim and
+// its aliases.
+func InstallNeovimRelease(settings NeovimSettings) error {
+	version := settings.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	info, err := fetchGitHubRelease(neovimRepo, version)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(settings.prefix(), info.TagName)
+	if info.TagName == "nightly" || info.TagName == "stable" {
+		// The nightly and stable tags move, so they're always installed again
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove Neovim %s: %w", info.TagName, err)
+		}
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		fmt.Printf("Neovim %s is already installed, skipping.\n", info.TagName)
+	} else if err := installNeovimTree(info, dir); err != nil {
+		return err
+	}
+
+	if err := ReplaceSymlink(dir, settings.current()); err != nil {
+		return fmt.Errorf("failed to switch to Neovim %s: %w", info.TagName, err)
+	}
+
+	return linkNeovim(settings)
+}
+
+func installNeovimTree(info githubRelease, dir string) error {
+	candidates, err := neovimAssets()
+	if err != nil {
+		return err
+	}
+
+	var asset githubAsset
+	for _, name := range candidates {
+		if found, ok := info.asset(name); ok {
+			asset = found
+			break
+		}
+	}
+	if asset.Name == "" {
+		return fmt.Errorf("Neovim %s has no release for %s/%s", info.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+
+	checksum, err := neovimChecksum(info, asset)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dir), err)
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".install-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	color.Blue("Installing Neovim %s...", info.TagName)
+	tarball := filepath.Join(tmp, asset.Name)
+	if err := DownloadFile(asset.URL, tarball, checksum); err != nil {
+		return err
+	}
+
+	extracted := filepath.Join(tmp, "nvim")
+	if err := ExtractTarGz(tarball, extracted, 1); err != nil {
+		return err
+	}
+
+	return os.Rename(extracted, dir)
+}
+
+// neovimChecksum finds the SHA-256 of asset: releases before 0.10.4 ship
+// a .sha256sum per asset, later ones a shasum.txt, and GitHub's digest
+// covers the rest.
+func neovimChecksum(info githubRelease, asset githubAsset) (string, error) {
+	for _, name := range []string{asset.Name + ".sha256sum", "shasum.txt"} {
+		if checksums, ok := info.asset(name); ok {
+			return fetchChecksum(checksums, asset.Name)
+		}
+	}
+
+	if asset.Digest == "" {
+		return "", fmt.Errorf("Neovim %s has no checksum for %s", info.TagName, asset.Name)
+	}
+
+	return strings.TrimPrefix(asset.Digest, "sha256:"), nil
+}
+
+// withinDir reports whether path is dir or below it.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// linkNeovim links nvim into LocalBinPath and makes the aliases run it,
+// through links next to it or update-alternatives.
+func linkNeovim(settings NeovimSettings) error {
+	nvim := filepath.Join(settings.current(), "bin", "nvim")
+
+	if err := os.MkdirAll(LocalBinPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", LocalBinPath(), err)
+	}
+
+	links := []string{"nvim"}
+	if settings.Alternatives {
+		if _, err := exec.LookPath("update-alternatives"); err != nil {
+			return fmt.Errorf("alternatives are enabled but update-alternatives isn't installed")
+		}
+		if withinDir(HomePath(), settings.prefix()) {
+			return fmt.Errorf("alternatives are system wide, so they need a Neovim prefix outside of %s", HomePath())
+		}
+
+		for _, alias := range settings.Aliases {
+			color.Blue("Registering Neovim as the %s alternative...", alias)
+			if err := RunCmd(PrivilegedCommand("update-alternatives", "--install", filepath.Join("/usr/bin", alias), alias, nvim, "60")); err != nil {
+				return err
+			}
+		}
+	} else {
+		links = append(links, settings.Aliases...)
+	}
+
+	// Neovim finds its runtime files through the resolved path of the
+	// binary, so plain symlinks work
+	for _, name := range links {
+		if err := ReplaceSymlink(nvim, filepath.Join(LocalBinPath(), name)); err != nil {
+			return fmt.Errorf("failed to link %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
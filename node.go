@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// nodeDownloadURL serves the official Node release tarballs and their index.
+var nodeDownloadURL = "https://nodejs.org/dist/"
+
+// NodeSettings configures the Node installation.
+type NodeSettings struct {
+	// Version is lts, latest, a version prefix like 22 or an exact version
+	Version string `yaml:"version"`
+	// Corepack enables the pnpm and yarn shims that ship with Node
+	Corepack bool `yaml:"corepack"`
+	// Packages are installed globally with npm
+	Packages []string `yaml:"packages"`
+}
+
+func DefaultNodeSettings() NodeSettings {
+	return NodeSettings{
+		Version:  "lts",
+		Corepack: true,
+	}
+}
+
+type nodeRelease struct {
+	Version string `json:"version"`
+	// LTS is the codename of an LTS release and false otherwise
+	LTS any `json:"lts"`
+}
+
+// NodeVersionsPath holds one directory per installed Node version.
+func NodeVersionsPath() string {
+	return filepath.Join(DataDir(), "node", "versions")
+}
+
+// NodeCurrentPath is a symlink to the Node version in use; its bin
+// directory is the one on PATH.
+func NodeCurrentPath() string {
+	return filepath.Join(DataDir(), "node", "current")
+}
+
+// SetupNode installs Node from the official tarballs, then configures it.
+func SetupNode() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	version, err := ResolveNodeVersion(config.Node.Version)
+	if err != nil {
+		return err
+	}
+
+	if err := InstallNodeVersion(version); err != nil {
+		return err
+	}
+
+	if err := ReplaceSymlink(filepath.Join(NodeVersionsPath(), version), NodeCurrentPath()); err != nil {
+		return fmt.Errorf("failed to switch to Node %s: %w", version, err)
+	}
+
+	if err := PrependToPath(filepath.Join(NodeCurrentPath(), "bin")); err != nil {
+		return err
+	}
+
+	return ConfigureNode(config.Node)
+}
+
+// ConfigureNode enables corepack and installs the global npm packages.
+// It works with both the Node devtools installs and a system Node.
+func ConfigureNode(settings NodeSettings) error {
+	if settings.Corepack {
+		// Newer Node releases no longer bundle corepack
+		if _, err := exec.LookPath(nodeBinary("corepack")); err != nil {
+			if err := InstallNpmPackages("corepack"); err != nil {
+				return err
+			}
+		}
+
+		color.Blue("Enabling corepack...")
+		if err := runNode("corepack", "enable"); err != nil {
+			return err
+		}
+	}
+
+	return InstallNpmPackages(settings.Packages...)
+}
+
+// InstallNpmPackages installs packages globally with npm.
+func InstallNpmPackages(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	color.Blue("Installing npm packages %s...", strings.Join(packages, ", "))
+	return runNode("npm", append([]string{"install", "-g"}, packages...)...)
+}
+
+// nodeBinary returns the path of a Node executable devtools installed, or
+// just its name to look up on PATH when Node came from the system.
+func nodeBinary(name string) string {
+	path := filepath.Join(NodeCurrentPath(), "bin", name)
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	return name
+}
+
+// runNode runs a Node executable like npm. npm and corepack are scripts
+// that find node through PATH, so the Node devtools installed goes first.
+// The global directory of a system Node belongs to root.
+func runNode(name string, args ...string) error {
+	binary := nodeBinary(name)
+	if binary == name {
+		return RunCommand(append([]string{Privileged(name)}, args...)...)
+	}
+
+	fmt.Printf("Running command: %s %s\n", name, strings.Join(args, " "))
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), "PATH="+filepath.Dir(binary)+string(os.PathListSeparator)+os.Getenv("PATH"))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ResolveNodeVersion turns lts, latest or a version prefix into the newest
+// matching Node release, e.g. 22 into 22.11.0.
+func ResolveNodeVersion(version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+
+	resp, err := http.Get(nodeDownloadURL + "index.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list Node releases: %s", resp.Status)
+	}
+
+	// The index lists the newest release first
+	releases := []nodeRelease{}
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("failed to parse Node releases: %w", err)
+	}
+
+	for _, release := range releases {
+		candidate := strings.TrimPrefix(release.Version, "v")
+
+		switch {
+		case version == "lts":
+			if lts, ok := release.LTS.(string); !ok || lts == "" {
+				continue
+			}
+		case version == "latest":
+		case candidate != version && !strings.HasPrefix(candidate, version+"."):
+			continue
+		}
+
+		return candidate, nil
+	}
+
+	return "", fmt.Errorf("no Node release matches %s", version)
+}
+
+// nodePlatform returns the platform suffix of the Node tarball names.
+func nodePlatform() (string, error) {
+	arch := map[string]string{
+		"amd64":   "x64",
+		"arm64":   "arm64",
+		"arm":     "armv7l",
+		"ppc64le": "ppc64le",
+		"s390x":   "s390x",
+	}[runtime.GOARCH]
+	if arch == "" {
+		return "", fmt.Errorf("no Node release for %s", runtime.GOARCH)
+	}
+
+	return runtime.GOOS + "-" + arch, nil
+}
+
+// InstallNodeVersion downloads a Node release, verifies it against the
+// release's SHASUMS256.txt and unpacks it into its own directory under
+// NodeVersionsPath. Installed versions are skipped.
+func InstallNodeVersion(version string) error {
+	dir := filepath.Join(NodeVersionsPath(), version)
+	if _, err := os.Stat(dir); err == nil {
+		fmt.Printf("Node %s is already installed, skipping.\n", version)
+		return nil
+	}
+
+	platform, err := nodePlatform()
+	if err != nil {
+		return err
+	}
+
+	releaseURL := fmt.Sprintf("%sv%s/", nodeDownloadURL, version)
+	filename := fmt.Sprintf("node-v%s-%s.tar.gz", version, platform)
+
+	checksum, err := nodeChecksum(releaseURL, filename)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(NodeVersionsPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", NodeVersionsPath(), err)
+	}
+
+	tmp, err := os.MkdirTemp(NodeVersionsPath(), ".install-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	color.Blue("Installing Node %s...", version)
+	tarball := filepath.Join(tmp, filename)
+	if err := DownloadFile(releaseURL+filename, tarball, checksum); err != nil {
+		return err
+	}
+
+	extracted := filepath.Join(tmp, "node")
+	if err := ExtractTarGz(tarball, extracted, 1); err != nil {
+		return err
+	}
+
+	return os.Rename(extracted, dir)
+}
+
+// nodeChecksum looks up the SHA-256 of filename in the SHASUMS256.txt
+// published with the release.
+func nodeChecksum(releaseURL, filename string) (string, error) {
+	resp, err := http.Get(releaseURL + "SHASUMS256.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download Node checksums: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == filename {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read Node checksums: %w", err)
+	}
+
+	return "", fmt.Errorf("no checksum for %s", filename)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const ohMyZshInstallScript = "https://raw.githubusercontent.com/ohmyzsh/ohmyzsh/master/tools/install.sh"
+
+// ZshSettings configures Oh My Zsh in .zshrc.
+type ZshSettings struct {
+	Theme   string   `yaml:"theme"`
+	Plugins []string `yaml:"plugins"`
+	// ExternalPlugins are GitHub repositories cloned into $ZSH_CUSTOM/plugins
+	// and enabled alongside Plugins, e.g. zsh-users/zsh-autosuggestions
+	ExternalPlugins []string `yaml:"external_plugins"`
+}
+
+func DefaultZshSettings() ZshSettings {
+	return ZshSettings{
+		Theme:   "robbyrussell",
+		Plugins: []string{"git"},
+		ExternalPlugins: []string{
+			"zsh-users/zsh-autosuggestions",
+			"zsh-users/zsh-syntax-highlighting",
+		},
+	}
+}
+
+func OhMyZshPath() string {
+	if dir := os.Getenv("ZSH"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(HomePath(), ".oh-my-zsh")
+}
+
+func ZshCustomPath() string {
+	if dir := os.Getenv("ZSH_CUSTOM"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(OhMyZshPath(), "custom")
+}
+
+func ZshrcPath() string {
+	return filepath.Join(HomePath(), ".zshrc")
+}
+
+// SetupOhMyZsh installs Oh My Zsh without prompting, then installs the
+// external plugins and writes the theme and plugin list into .zshrc.
+func SetupOhMyZsh() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	settings := config.Zsh
+
+	if _, err := os.Stat(OhMyZshPath()); os.IsNotExist(err) {
+		// The installer would otherwise start zsh, change the shell and
+		// replace an existing .zshrc. Alpine has no bash, so it runs in sh
+		color.Blue("Installing Oh My Zsh...")
+		script := fmt.Sprintf(`RUNZSH=no CHSH=no KEEP_ZSHRC=yes sh -c "$(curl -fsSL %s)" "" --unattended`, ohMyZshInstallScript)
+		if err := RunPOSIXShell(script); err != nil {
+			return err
+		}
+	}
+
+	plugins := append([]string{}, settings.Plugins...)
+	for _, repository := range settings.ExternalPlugins {
+		name := filepath.Base(repository)
+		dir := filepath.Join(ZshCustomPath(), "plugins", name)
+
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			color.Blue("Installing zsh plugin %s...", name)
+			if err := RunCommand("git clone --depth 1", "https://github.com/"+repository, dir); err != nil {
+				return err
+			}
+		}
+
+		if !containsString(plugins, name) {
+			plugins = append(plugins, name)
+		}
+	}
+
+	content, err := os.ReadFile(ZshrcPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .zshrc: %w", err)
+	}
+
+	color.Blue("Configuring Oh My Zsh theme and plugins...")
+	return WriteContentToFile(ConfigureZshrc(string(content), settings.Theme, plugins), ZshrcPath())
+}
+
+var (
+	zshThemePattern   = regexp.MustCompile(`(?m)^ZSH_THEME=.*$`)
+	zshPluginsPattern = regexp.MustCompile(`(?m)^plugins=\([^)]*\)`)
+	zshSourcePattern  = regexp.MustCompile(`(?m)^source \$ZSH/oh-my-zsh\.sh.*$`)
+)
+
+// ConfigureZshrc sets the Oh My Zsh theme and plugins in the contents of a
+// .zshrc. Settings that aren't there yet are added before Oh My Zsh is
+// sourced, and the whole Oh My Zsh setup is appended to a .zshrc without it.
+func ConfigureZshrc(content, theme string, plugins []string) string {
+	themeLine := fmt.Sprintf("ZSH_THEME=%q", theme)
+	pluginsLine := fmt.Sprintf("plugins=(%s)", strings.Join(plugins, " "))
+
+	if !zshSourcePattern.MatchString(content) {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + strings.Join([]string{
+			`export ZSH="$HOME/.oh-my-zsh"`,
+			themeLine,
+			pluginsLine,
+			"source $ZSH/oh-my-zsh.sh",
+		}, "\n") + "\n"
+	}
+
+	missing := []string{}
+
+	if zshThemePattern.MatchString(content) {
+		content = zshThemePattern.ReplaceAllLiteralString(content, themeLine)
+	} else {
+		missing = append(missing, themeLine)
+	}
+
+	if zshPluginsPattern.MatchString(content) {
+		content = zshPluginsPattern.ReplaceAllLiteralString(content, pluginsLine)
+	} else {
+		missing = append(missing, pluginsLine)
+	}
+
+	if len(missing) > 0 {
+		source := zshSourcePattern.FindStringIndex(content)
+		content = content[:source[0]] + strings.Join(missing, "\n") + "\n" + content[source[0]:]
+	}
+
+	return content
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
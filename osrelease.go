@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const osReleasePath = "/etc/os-release"
+
+// ParseOSRelease parses the KEY=value pairs of an os-release file.
+func ParseOSRelease(content string) map[string]string {
+	fields := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		fields[key] = strings.Trim(value, `"'`)
+	}
+
+	return fields
+}
+
+// readOSRelease returns the fields of the running system's os-release, or
+// nil when it has none.
+func readOSRelease() map[string]string {
+	content, err := os.ReadFile(osReleasePath)
+	if err != nil {
+		return nil
+	}
+
+	return ParseOSRelease(string(content))
+}
+
+// OSReleaseID returns the ID of the running distribution, e.g. rocky, for
+// backends that cover several of them.
+func OSReleaseID() string {
+	return readOSRelease()["ID"]
+}
+
+// DetectOS returns the OS choice matching the running system, or an empty
+// string if it isn't one devtools knows about.
+func DetectOS() string {
+	if runtime.GOOS == "darwin" {
+		return "MacOS"
+	}
+
+	fields := readOSRelease()
+	if fields == nil {
+		return ""
+	}
+
+	ids := append([]string{fields["ID"]}, strings.Fields(fields["ID_LIKE"])...)
+
+	for _, id := range ids {
+		switch id {
+		case "ubuntu", "debian":
+			return "Ubuntu"
+		case "fedora", "rhel", "centos", "rocky", "almalinux":
+			return "Fedora"
+		case "arch":
+			return "Arch"
+		case "alpine":
+			return "Alpine"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PackageManager installs, removes and queries system packages.
+type PackageManager interface {
+	Name() string
+	Update() error
+	Install(packages ...string) error
+	Remove(packages ...string) error
+	IsInstalled(pkg string) (bool, error)
+}
+
+// MissingPackages returns the packages that pm reports as not installed,
+// preserving order and dropping duplicates.
+func MissingPackages(pm PackageManager, packages []string) ([]string, error) {
+	missing := []string{}
+	seen := map[string]bool{}
+
+	for _, pkg := range packages {
+		if seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+
+		installed, err := pm.IsInstalled(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s package %s: %w", pm.Name(), pkg, err)
+		}
+
+		if installed {
+			fmt.Printf("%s is already installed, skipping.\n", pkg)
+			continue
+		}
+
+		missing = append(missing, pkg)
+	}
+
+	return missing, nil
+}
+
+// InstalledPackages returns the packages that pm reports as installed, so
+// removing packages that aren't there doesn't fail.
+func InstalledPackages(pm PackageManager, packages []string) ([]string, error) {
+	installed := []string{}
+	seen := map[string]bool{}
+
+	for _, pkg := range packages {
+		if seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+
+		ok, err := pm.IsInstalled(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s package %s: %w", pm.Name(), pkg, err)
+		}
+
+		if !ok {
+			fmt.Printf("%s is not installed, skipping.\n", pkg)
+			continue
+		}
+
+		installed = append(installed, pkg)
+	}
+
+	return installed, nil
+}
+
+// BatchPackages collects the plain packages needed by the selected tools so
+// they can be installed in a single package manager transaction.
+func BatchPackages(tools []string, packages map[string][]string) []string {
+	batch := []string{}
+	seen := map[string]bool{}
+
+	for _, tool := range tools {
+		for _, pkg := range packages[tool] {
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+			batch = append(batch, pkg)
+		}
+	}
+
+	return batch
+}
+
+type Apt struct{}
+
+func (a *Apt) Name() string {
+	return "apt"
+}
+
+func (a *Apt) Update() error {
+	return RunCommand("sudo apt update")
+}
+
+func (a *Apt) Install(packages ...string) error {
+	missing, err := MissingPackages(a, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo apt install -y"}, missing...)...)
+}
+
+func (a *Apt) Remove(packages ...string) error {
+	installed, err := InstalledPackages(a, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo apt remove -y"}, installed...)...)
+}
+
+func (a *Apt) IsInstalled(pkg string) (bool, error) {
+	out, err := exec.Command("dpkg-query", "-W", "-f=${Status}", pkg).Output()
+	if err != nil {
+		// dpkg-query exits non-zero for packages it has never seen
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return strings.HasSuffix(strings.TrimSpace(string(out)), "install ok installed"), nil
+}
+
+type Brew struct{}
+
+func (b *Brew) Name() string {
+	return "brew"
+}
+
+func (b *Brew) Update() error {
+	return RunCommand("brew update")
+}
+
+func (b *Brew) Install(packages ...string) error {
+	missing, err := MissingPackages(b, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"brew install"}, missing...)...)
+}
+
+func (b *Brew) Remove(packages ...string) error {
+	installed, err := InstalledPackages(b, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"brew uninstall"}, installed...)...)
+}
+
+func (b *Brew) IsInstalled(pkg string) (bool, error) {
+	if err := exec.Command("brew", "list", "--versions", pkg).Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+type Dnf struct{}
+
+func (d *Dnf) Name() string {
+	return "dnf"
+}
+
+func (d *Dnf) Update() error {
+	return RunCommand("sudo dnf makecache")
+}
+
+func (d *Dnf) Install(packages ...string) error {
+	missing, err := MissingPackages(d, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo dnf install -y"}, missing...)...)
+}
+
+func (d *Dnf) Remove(packages ...string) error {
+	installed, err := InstalledPackages(d, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo dnf remove -y"}, installed...)...)
+}
+
+func (d *Dnf) IsInstalled(pkg string) (bool, error) {
+	if err := exec.Command("rpm", "-q", "--quiet", pkg).Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// InstallGroup installs a package group by its id. Ids are used instead of
+// display names like "Development Tools" because RunCommand splits on spaces.
+func (d *Dnf) InstallGroup(group string) error {
+	return RunCommand("sudo dnf group install -y", group)
+}
+
+type Pacman struct{}
+
+func (p *Pacman) Name() string {
+	return "pacman"
+}
+
+// Update syncs and upgrades the whole system, since Arch doesn't support
+// partial upgrades.
+func (p *Pacman) Update() error {
+	return RunCommand("sudo pacman -Syu --noconfirm")
+}
+
+func (p *Pacman) Install(packages ...string) error {
+	missing, err := MissingPackages(p, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo pacman -S --needed --noconfirm"}, missing...)...)
+}
+
+func (p *Pacman) Remove(packages ...string) error {
+	installed, err := InstalledPackages(p, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{"sudo pacman -Rns --noconfirm"}, installed...)...)
+}
+
+func (p *Pacman) IsInstalled(pkg string) (bool, error) {
+	if err := exec.Command("pacman", "-Q", pkg).Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+type Apk struct{}
+
+func (a *Apk) Name() string {
+	return "apk"
+}
+
+func (a *Apk) Update() error {
+	return RunCommand(Privileged("apk update"))
+}
+
+func (a *Apk) Install(packages ...string) error {
+	missing, err := MissingPackages(a, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{Privileged("apk add --no-cache")}, missing...)...)
+}
+
+func (a *Apk) Remove(packages ...string) error {
+	installed, err := InstalledPackages(a, packages)
+	if err != nil {
+		return err
+	}
+
+	if len(installed) == 0 {
+		return nil
+	}
+
+	return RunCommand(append([]string{Privileged("apk del")}, installed...)...)
+}
+
+func (a *Apk) IsInstalled(pkg string) (bool, error) {
+	if err := exec.Command("apk", "info", "-e", pkg).Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	pluginPrefix = "devtools-plugin-"
+	pluginUsage  = "usage: devtools plugin list | uninstall <name>"
+	// pluginProtocol is the version of the protocol sent with each request
+	pluginProtocol = 1
+	// pluginQueryTimeout bounds the requests that only look at the system
+	pluginQueryTimeout = 30 * time.Second
+)
+
+// PluginRequest is written as JSON to the stdin of a plugin. Action is one
+// of describe, detect, install, verify or uninstall.
+type PluginRequest struct {
+	Protocol int    `json:"protocol"`
+	Action   string `json:"action"`
+	// OS is the OS as in the OS menu, lowercased, e.g. ubuntu
+	OS string `json:"os"`
+}
+
+// PluginResponse is read as JSON from the stdout of a plugin, which keeps
+// stderr for its own output. Each action fills in its own fields.
+type PluginResponse struct {
+	// Description and Requires answer describe
+	Description string   `json:"description"`
+	Requires    []string `json:"requires"`
+	// Installed and Version answer detect
+	Installed bool   `json:"installed"`
+	Version   string `json:"version"`
+	// OK answers verify
+	OK bool `json:"ok"`
+	// Error reports a failed action
+	Error string `json:"error"`
+}
+
+// PluginsDir holds plugins installed for devtools only. They take
+// precedence over plugins on PATH.
+func PluginsDir() string {
+	return filepath.Join(DataDir(), "plugins")
+}
+
+// DiscoverPlugins finds the devtools-plugin-<name> executables in
+// PluginsDir and on PATH, and describes each as a custom tool called name.
+// Plugins that fail to describe themselves are skipped with a warning.
+func DiscoverPlugins() []CustomTool {
+	dirs := append([]string{PluginsDir()}, filepath.SplitList(os.Getenv("PATH"))...)
+
+	plugins := []CustomTool{}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), pluginPrefix)
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+
+			response, err := callPlugin(path, "describe", "")
+			if err != nil {
+				color.Red("Skipping plugin %s: %v", name, err)
+				continue
+			}
+
+			plugins = append(plugins, CustomTool{
+				Name:        name,
+				Description: response.Description,
+				Requires:    response.Requires,
+				Plugin:      path,
+			})
+		}
+	}
+
+	return plugins
+}
+
+// callPlugin sends a request for action to the plugin at path and reads
+// its response. Plugins signal failure with an error, a non-zero exit
+// status or both.
+func callPlugin(path, action, osName string) (PluginResponse, error) {
+	response := PluginResponse{}
+
+	request, err := json.Marshal(PluginRequest{
+		Protocol: pluginProtocol,
+		Action:   action,
+		OS:       strings.ToLower(osName),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	ctx := context.Background()
+	if action != "install" && action != "uninstall" {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pluginQueryTimeout)
+		defer cancel()
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return response, fmt.Errorf("%s failed: %w", action, runErr)
+		}
+		return response, fmt.Errorf("invalid %s response: %w", action, err)
+	}
+
+	if response.Error != "" {
+		return response, fmt.Errorf("%s failed: %s", action, response.Error)
+	}
+	if runErr != nil {
+		return response, fmt.Errorf("%s failed: %w", action, runErr)
+	}
+
+	return response, nil
+}
+
+// InstallPlugin installs the tool a plugin provides unless it detects the
+// tool as installed, then has the plugin verify the install.
+func InstallPlugin(tool CustomTool, osName string) error {
+	detected, err := callPlugin(tool.Plugin, "detect", osName)
+	if err != nil {
+		return err
+	}
+	if detected.Installed {
+		fmt.Printf("%s %s is already installed, skipping.\n", tool.Name, detected.Version)
+		return nil
+	}
+
+	color.Blue("Installing %s...", tool.Name)
+	if _, err := callPlugin(tool.Plugin, "install", osName); err != nil {
+		return err
+	}
+
+	verified, err := callPlugin(tool.Plugin, "verify", osName)
+	if err != nil {
+		return err
+	}
+	if !verified.OK {
+		return fmt.Errorf("%s failed verification", tool.Name)
+	}
+
+	return nil
+}
+
+// PluginCommand runs `devtools plugin`.
+func PluginCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(pluginUsage)
+	}
+
+	switch {
+	case args[0] == "list":
+		for _, plugin := range DiscoverPlugins() {
+			fmt.Printf("%s %s (%s)\n", plugin.Name, plugin.Description, plugin.Plugin)
+		}
+		return nil
+	case args[0] == "uninstall" && len(args) == 2:
+		return UninstallPlugin(args[1])
+	default:
+		return errors.New(pluginUsage)
+	}
+}
+
+// UninstallPlugin removes the tool the named plugin provides.
+func UninstallPlugin(name string) error {
+	tool, ok := findCustomTool(DiscoverPlugins(), name)
+	if !ok {
+		return fmt.Errorf("no plugin named %s", name)
+	}
+
+	color.Blue("Uninstalling %s...", name)
+	if _, err := callPlugin(tool.Plugin, "uninstall", DetectOS()); err != nil {
+		return err
+	}
+
+	state, err := loadCustomToolState()
+	if err != nil {
+		return err
+	}
+
+	delete(state, name)
+	return saveCustomToolState(state)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiscoverProjects lists the projects below each configured root. Hidden and
+// ignored directories are skipped, and git repositories and worktrees are
+// offered as a whole without searching inside them.
+func DiscoverProjects(config SessionizerConfig) ([]string, error) {
+	projects := []string{}
+	seen := map[string]bool{}
+
+	for _, root := range config.Roots {
+		found, err := discoverRoot(root, config)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, project := range found {
+			if !seen[project] {
+				seen[project] = true
+				projects = append(projects, project)
+			}
+		}
+	}
+
+	return projects, nil
+}
+
+func discoverRoot(root ProjectRoot, config SessionizerConfig) ([]string, error) {
+	rootPath := ExpandHome(root.Path)
+	if _, err := os.Stat(rootPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	depth := root.Depth
+	if depth == 0 {
+		depth = config.Depth
+	}
+
+	projects := []string{}
+	err := filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Directories we can't read are left out rather than failing
+			// the whole search; only the root itself has to be readable
+			if path != rootPath && d != nil && d.IsDir() && errors.Is(err, fs.ErrPermission) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if !d.IsDir() || path == rootPath {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(rootPath, path)
+		if strings.HasPrefix(d.Name(), ".") || IsIgnored(rel, config.Ignore) {
+			return filepath.SkipDir
+		}
+
+		if IsGitProject(path) {
+			projects = append(projects, path)
+			return filepath.SkipDir
+		}
+
+		if !root.GitOnly {
+			projects = append(projects, path)
+		}
+
+		if len(strings.Split(rel, string(filepath.Separator))) >= depth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", rootPath, err)
+	}
+
+	return projects, nil
+}
+
+// IsGitProject reports whether dir is a git repository or worktree. A
+// worktree, like a submodule, has a .git file pointing at the real git dir.
+func IsGitProject(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ".git"))
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() {
+		return true
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(string(content), "gitdir:")
+}
+
+// IsIgnored reports whether rel, a path relative to a project root, matches
+// any of the ignore globs by its base name or as a whole.
+func IsIgnored(rel string, globs []string) bool {
+	for _, glob := range globs {
+		if ok, _ := filepath.Match(glob, filepath.Base(rel)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(glob, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
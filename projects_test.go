@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// mkdirs creates dirs below root and returns root.
+func mkdirs(t *testing.T, root string, dirs ...string) string {
+	t.Helper()
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestDiscoverProjects(t *testing.T) {
+	root := mkdirs(t, t.TempDir(),
+		"work/api/.git",
+		"work/api/internal",
+		"work/web",
+		"work/web/src/deep",
+		"scratch",
+		".hidden/project",
+		"node_modules/pkg",
+	)
+	// A worktree has a .git file pointing at the real git dir
+	mkdirs(t, root, "work/api-feature")
+	if err := os.WriteFile(filepath.Join(root, "work/api-feature/.git"), []byte("gitdir: ../api/.git/worktrees/feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config SessionizerConfig
+		want   []string
+	}{
+		{
+			name: "depth limits the search",
+			config: SessionizerConfig{
+				Roots:  []ProjectRoot{{Path: root}},
+				Depth:  2,
+				Ignore: []string{"node_modules"},
+			},
+			// git projects aren't searched inside; hidden and ignored
+			// directories are skipped
+			want: []string{"scratch", "work", "work/api", "work/api-feature", "work/web"},
+		},
+		{
+			name: "git only",
+			config: SessionizerConfig{
+				Roots: []ProjectRoot{{Path: root, GitOnly: true}},
+				Depth: 3,
+			},
+			want: []string{"work/api", "work/api-feature"},
+		},
+		{
+			name: "root depth overrides the default",
+			config: SessionizerConfig{
+				Roots:  []ProjectRoot{{Path: filepath.Join(root, "work"), Depth: 3}},
+				Depth:  1,
+				Ignore: []string{"web/src/*"},
+			},
+			want: []string{"work/api", "work/api-feature", "work/web", "work/web/src"},
+		},
+		{
+			name: "duplicates across roots and missing roots",
+			config: SessionizerConfig{
+				Roots: []ProjectRoot{
+					{Path: filepath.Join(root, "work"), GitOnly: true},
+					{Path: filepath.Join(root, "missing")},
+					{Path: filepath.Join(root, "work")},
+				},
+				Depth: 1,
+			},
+			want: []string{"work/api", "work/api-feature", "work/web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, err := DiscoverProjects(tt.config)
+			if err != nil {
+				t.Fatalf("DiscoverProjects failed: %v", err)
+			}
+
+			got := []string{}
+			for _, project := range projects {
+				rel, _ := filepath.Rel(root, project)
+				got = append(got, rel)
+			}
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DiscoverProjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverProjectsPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read directories without permissions")
+	}
+
+	root := mkdirs(t, t.TempDir(), "readable", "locked")
+	locked := filepath.Join(root, "locked")
+	if err := os.Chmod(locked, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+
+	projects, err := DiscoverProjects(SessionizerConfig{
+		Roots: []ProjectRoot{{Path: root}},
+		Depth: 2,
+	})
+	if err != nil {
+		t.Fatalf("DiscoverProjects failed on an unreadable directory: %v", err)
+	}
+	if !slices.Contains(projects, filepath.Join(root, "readable")) {
+		t.Errorf("projects %v don't include the readable directory", projects)
+	}
+
+	// Only an unreadable root fails the search
+	_, err = DiscoverProjects(SessionizerConfig{
+		Roots: []ProjectRoot{{Path: locked}},
+		Depth: 2,
+	})
+	if err == nil {
+		t.Error("DiscoverProjects succeeded on an unreadable root")
+	}
+}
+
+func TestUsageRank(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := Usage{
+		// 1 use within the hour scores 4
+		"/recent": {Count: 1, LastUsed: now.Add(-time.Minute)},
+		// 3 uses within the day score 6
+		"/today": {Count: 3, LastUsed: now.Add(-2 * time.Hour)},
+		// 10 uses within the week score 5
+		"/week": {Count: 10, LastUsed: now.Add(-3 * 24 * time.Hour)},
+		// 8 old uses score 2
+		"/old": {Count: 8, LastUsed: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	projects := []string{"/unused-a", "/old", "/recent", "/unused-b", "/week", "/today"}
+	usage.Rank(projects, now)
+
+	want := []string{"/today", "/week", "/recent", "/old", "/unused-a", "/unused-b"}
+	if !slices.Equal(projects, want) {
+		t.Errorf("Rank() = %v, want %v", projects, want)
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	first := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	for _, now := range []time.Time{first, second} {
+		if err := RecordUsage("/project", now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := LoadUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := usage["/project"]
+	if entry.Count != 2 || !entry.LastUsed.Equal(second) {
+		t.Errorf("recorded %+v, want 2 uses last at %v", entry, second)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const uvInstallScript = "https://astral.sh/uv/install.sh"
+
+// PythonSettings configures the Python interpreters and CLI tools.
+type PythonSettings struct {
+	// Versions are interpreter versions installed with uv, e.g. 3.12. The
+	// system Python is the only one when empty
+	Versions []string `yaml:"versions"`
+	// Tools are CLI tools pipx installs into isolated environments
+	Tools []string `yaml:"tools"`
+	// Poetry holds poetry config settings, e.g. virtualenvs.in-project: true
+	Poetry map[string]string `yaml:"poetry"`
+}
+
+func DefaultPythonSettings() PythonSettings {
+	return PythonSettings{
+		Tools: []string{"poetry", "ruff", "black"},
+		Poetry: map[string]string{
+			"virtualenvs.in-project": "true",
+		},
+	}
+}
+
+// PipxBinPath is where pipx links the tools it installs.
+func PipxBinPath() string {
+	if dir := os.Getenv("PIPX_BIN_DIR"); dir != "" {
+		return dir
+	}
+
+	return LocalBinPath()
+}
+
+// SetupPython installs the configured interpreter versions and pipx tools
+// on top of the system Python and pipx.
+func SetupPython() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	settings := config.Python
+
+	if err := InstallPythonVersions(settings.Versions...); err != nil {
+		return err
+	}
+
+	if err := InstallPipxTools(settings.Tools...); err != nil {
+		return err
+	}
+
+	if err := AddToRCFiles("export PATH=$PATH:" + PipxBinPath()); err != nil {
+		return err
+	}
+
+	if containsString(settings.Tools, "poetry") {
+		return ConfigurePoetry(settings.Poetry)
+	}
+
+	return nil
+}
+
+// SetupPoetry installs poetry with pipx and configures it.
+func SetupPoetry() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := InstallPipxTools("poetry"); err != nil {
+		return err
+	}
+
+	if err := AddToRCFiles("export PATH=$PATH:" + PipxBinPath()); err != nil {
+		return err
+	}
+
+	return ConfigurePoetry(config.Python.Poetry)
+}
+
+// InstallPythonVersions installs interpreter versions with uv, installing
+// uv itself into LocalBinPath first when it's missing.
+func InstallPythonVersions(versions ...string) error {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	uv := filepath.Join(LocalBinPath(), "uv")
+	if _, err := os.Stat(uv); os.IsNotExist(err) {
+		color.Blue("Installing uv...")
+		// The install dir goes through the environment so it isn't split
+		cmd := exec.Command("sh", "-c", fmt.Sprintf("curl -LsSf %s | sh", uvInstallScript))
+		cmd.Env = append(os.Environ(), "UV_INSTALL_DIR="+LocalBinPath(), "UV_NO_MODIFY_PATH=1")
+		if err := RunCmd(cmd); err != nil {
+			return err
+		}
+	}
+
+	color.Blue("Installing Python %v...", versions)
+	return RunCmd(exec.Command(uv, append([]string{"python", "install"}, versions...)...))
+}
+
+// InstallPipxTools installs each tool into its own environment with pipx.
+// Tools pipx already manages are left as they are, so selecting both python
+// and poetry installs poetry once.
+func InstallPipxTools(tools ...string) error {
+	installed := pipxTools()
+	for _, tool := range tools {
+		if installed[tool] {
+			fmt.Printf("%s is already installed, skipping.\n", tool)
+			continue
+		}
+
+		color.Blue("Installing %s...", tool)
+		if err := RunCmd(exec.Command("pipx", "install", tool)); err != nil {
+			return fmt.Errorf("failed to install %s: %w", tool, err)
+		}
+		installed[tool] = true
+	}
+
+	return nil
+}
+
+// pipxTools returns the packages pipx manages. It's empty when pipx can't
+// list them, e.g. versions before --short, and pipx install then skips
+// installed packages itself.
+func pipxTools() map[string]bool {
+	tools := map[string]bool{}
+
+	out, err := exec.Command("pipx", "list", "--short").Output()
+	if err != nil {
+		return tools
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		// Each line is the package followed by its version
+		if fields := strings.Fields(line); len(fields) > 0 {
+			tools[fields[0]] = true
+		}
+	}
+
+	return tools
+}
+
+// ConfigurePoetry applies poetry config settings globally.
+func ConfigurePoetry(settings map[string]string) error {
+	poetry := filepath.Join(PipxBinPath(), "poetry")
+	if _, err := os.Stat(poetry); os.IsNotExist(err) {
+		poetry = "poetry"
+	}
+
+	keys := []string{}
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	color.Blue("Configuring poetry...")
+	for _, key := range keys {
+		if err := RunCmd(exec.Command(poetry, "config", key, settings[key])); err != nil {
+			return fmt.Errorf("failed to set poetry %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
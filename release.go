@@ -0,0 +1,442 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const releaseUsage = "usage: devtools release install [name...] | list"
+
+// githubAPIURL is the GitHub REST API releases are looked up with.
+var githubAPIURL = "https://api.github.com"
+
+// GitHubRelease installs a single binary from the assets of a GitHub
+// release. Asset, Binary and Checksums may use the placeholders {version},
+// {tag}, {os} and {arch}; Checksums may also use {asset}.
+type GitHubRelease struct {
+	// Name is the executable installed into LocalBinPath; the repository
+	// name when empty
+	Name string `yaml:"name"`
+	// Repo is the repository as owner/repo
+	Repo string `yaml:"repo"`
+	// Version is a release tag like v0.40.2, or latest
+	Version string `yaml:"version"`
+	// Asset is the name of the release asset to download
+	Asset string `yaml:"asset"`
+	// Binary is the path of the executable inside an archive asset; the
+	// asset is the executable itself when empty
+	Binary string `yaml:"binary"`
+	// Checksums is the asset holding the SHA-256 of Asset, either a
+	// checksums.txt style list or a single hash
+	Checksums string `yaml:"checksums"`
+	// Insecure installs the asset unverified when there's neither a
+	// checksums asset nor a digest from GitHub
+	Insecure bool `yaml:"insecure"`
+	// OS and Arch rename the Go names {os} and {arch} stand for, e.g.
+	// amd64: x86_64
+	OS   map[string]string `yaml:"os"`
+	Arch map[string]string `yaml:"arch"`
+}
+
+func DefaultReleases() []GitHubRelease {
+	return []GitHubRelease{
+		{
+			Repo:      "jesseduffield/lazygit",
+			Version:   "latest",
+			Asset:     "lazygit_{version}_{os}_{arch}.tar.gz",
+			Binary:    "lazygit",
+			Checksums: "checksums.txt",
+			OS:        map[string]string{"linux": "Linux", "darwin": "Darwin"},
+			Arch:      map[string]string{"amd64": "x86_64"},
+		},
+		{
+			Repo:      "jesseduffield/lazydocker",
+			Version:   "latest",
+			Asset:     "lazydocker_{version}_{os}_{arch}.tar.gz",
+			Binary:    "lazydocker",
+			Checksums: "checksums.txt",
+			OS:        map[string]string{"linux": "Linux", "darwin": "Darwin"},
+			Arch:      map[string]string{"amd64": "x86_64"},
+		},
+	}
+}
+
+func (r GitHubRelease) BinaryName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return path.Base(r.Repo)
+}
+
+// TagOrLatest returns the configured release tag, latest by default.
+func (r GitHubRelease) TagOrLatest() string {
+	if r.Version == "" {
+		return "latest"
+	}
+
+	return r.Version
+}
+
+// expand fills in the placeholders of pattern for the release tag.
+func (r GitHubRelease) expand(pattern, tag string) string {
+	goos, arch := runtime.GOOS, runtime.GOARCH
+	if name, ok := r.OS[goos]; ok {
+		goos = name
+	}
+	if name, ok := r.Arch[arch]; ok {
+		arch = name
+	}
+
+	return strings.NewReplacer(
+		"{version}", strings.TrimPrefix(tag, "v"),
+		"{tag}", tag,
+		"{os}", goos,
+		"{arch}", arch,
+	).Replace(pattern)
+}
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	// Digest is the sha256:<hex> GitHub computed for the asset, when known
+	Digest string `json:"digest"`
+}
+
+func (r githubRelease) asset(name string) (githubAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+
+	return githubAsset{}, false
+}
+
+func releaseStatePath() string {
+	return filepath.Join(StateDir(), "releases.json")
+}
+
+// ReleaseCommand runs `devtools release`.
+func ReleaseCommand(config *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(releaseUsage)
+	}
+
+	switch args[0] {
+	case "install":
+		return InstallReleases(config.Releases, args[1:]...)
+	case "list":
+		return listReleases(config.Releases)
+	default:
+		return errors.New(releaseUsage)
+	}
+}
+
+// SetupReleases installs the releases from the config.
+func SetupReleases() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	return InstallReleases(config.Releases)
+}
+
+// InstallReleases installs the named releases, or all of them when no
+// names are given.
+func InstallReleases(releases []GitHubRelease, names ...string) error {
+	found := map[string]bool{}
+
+	for _, release := range releases {
+		if len(names) > 0 && !containsString(names, release.BinaryName()) {
+			continue
+		}
+		found[release.BinaryName()] = true
+
+		if err := InstallRelease(release); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return fmt.Errorf("no release named %s in the config", name)
+		}
+	}
+
+	return AddToRCFiles("export PATH=$PATH:" + LocalBinPath())
+}
+
+// InstallRelease installs the binary of a release into LocalBinPath and
+// records the installed tag, skipping the download when that tag is
+// already installed.
+func InstallRelease(release GitHubRelease) error {
+	info, err := fetchGitHubRelease(release.Repo, release.TagOrLatest())
+	if err != nil {
+		return err
+	}
+
+	state, err := loadReleaseState()
+	if err != nil {
+		return err
+	}
+
+	name := release.BinaryName()
+	dest := filepath.Join(LocalBinPath(), name)
+	if _, err := os.Stat(dest); err == nil && state[name] == info.TagName {
+		fmt.Printf("%s %s is already installed, skipping.\n", name, info.TagName)
+		return nil
+	}
+
+	assetName := release.expand(release.Asset, info.TagName)
+	asset, ok := info.asset(assetName)
+	if !ok {
+		return fmt.Errorf("%s %s has no asset %s", release.Repo, info.TagName, assetName)
+	}
+
+	checksum, err := releaseChecksum(release, info, asset)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "devtools-release-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	color.Blue("Installing %s %s...", name, info.TagName)
+	download := filepath.Join(tmp, asset.Name)
+	if err := DownloadFile(asset.URL, download, checksum); err != nil {
+		return err
+	}
+
+	binary := download
+	if release.Binary != "" {
+		extracted := filepath.Join(tmp, "extracted")
+		if err := extractArchive(download, extracted, 0); err != nil {
+			return err
+		}
+		binary = filepath.Join(extracted, filepath.FromSlash(release.expand(release.Binary, info.TagName)))
+	}
+
+	if err := installExecutable(binary, dest); err != nil {
+		return err
+	}
+
+	state[name] = info.TagName
+	return saveReleaseState(state)
+}
+
+// extractArchive extracts a .tar.gz or .zip archive by its file name.
+func extractArchive(src, dest string, strip int) error {
+	switch {
+	case strings.HasSuffix(src, ".tar.gz"), strings.HasSuffix(src, ".tgz"):
+		return ExtractTarGz(src, dest, strip)
+	case strings.HasSuffix(src, ".zip"):
+		return ExtractZip(src, dest, strip)
+	default:
+		return fmt.Errorf("unsupported archive %s", filepath.Base(src))
+	}
+}
+
+// installExecutable copies src to dest through a temporary file, so a
+// running binary is replaced rather than overwritten.
+func installExecutable(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(src), err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	tmp := dest + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+
+	return os.Rename(tmp, dest)
+}
+
+func fetchGitHubRelease(repo, version string) (githubRelease, error) {
+	release := githubRelease{}
+
+	url := fmt.Sprintf("%s/repos/%s/releases/tags/%s", githubAPIURL, repo, version)
+	if version == "latest" {
+		url = fmt.Sprintf("%s/repos/%s/releases/latest", githubAPIURL, repo)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return release, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	// Anonymous requests are rate limited to 60 an hour
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return release, fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return release, fmt.Errorf("failed to look up the %s release of %s: %s", version, repo, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return release, fmt.Errorf("failed to parse the release of %s: %w", repo, err)
+	}
+
+	return release, nil
+}
+
+// releaseChecksum returns the SHA-256 asset is verified against: from the
+// configured checksums asset, or else the digest GitHub reports. Without
+// either the release has to be marked insecure.
+func releaseChecksum(release GitHubRelease, info githubRelease, asset githubAsset) (string, error) {
+	if release.Checksums == "" {
+		if asset.Digest != "" {
+			return strings.TrimPrefix(asset.Digest, "sha256:"), nil
+		}
+		if !release.Insecure {
+			return "", fmt.Errorf("%s %s has no checksum for %s; configure checksums or set insecure: true", release.Repo, info.TagName, asset.Name)
+		}
+
+		color.Yellow("Installing %s unverified: %s has no checksum for it", asset.Name, release.Repo)
+		return "", nil
+	}
+
+	name := strings.ReplaceAll(release.expand(release.Checksums, info.TagName), "{asset}", asset.Name)
+	checksums, ok := info.asset(name)
+	if !ok {
+		return "", fmt.Errorf("%s %s has no checksums asset %s", release.Repo, info.TagName, name)
+	}
+
+	return fetchChecksum(checksums, asset.Name)
+}
+
+// fetchChecksum downloads a checksums asset and looks up the hash of the
+// asset called filename in it.
+func fetchChecksum(checksums githubAsset, filename string) (string, error) {
+	name := checksums.Name
+
+	resp, err := http.Get(checksums.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", name, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	checksum := ParseChecksum(string(content), filename)
+	if checksum == "" {
+		return "", fmt.Errorf("no checksum for %s in %s", filename, name)
+	}
+
+	return checksum, nil
+}
+
+// ParseChecksum finds the hash of filename in the output of sha256sum,
+// which may also be a lone hash for a single file.
+func ParseChecksum(content, filename string) string {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 1 && len(lines) == 1 {
+			return fields[0]
+		}
+		// sha256sum marks files hashed in binary mode with a *
+		if len(fields) == 2 && path.Base(strings.TrimPrefix(fields[1], "*")) == filename {
+			return fields[0]
+		}
+	}
+
+	return ""
+}
+
+func listReleases(releases []GitHubRelease) error {
+	state, err := loadReleaseState()
+	if err != nil {
+		return err
+	}
+
+	for _, release := range releases {
+		installed := state[release.BinaryName()]
+		if installed == "" {
+			installed = "not installed"
+		}
+
+		fmt.Printf("%s %s (%s@%s)\n", release.BinaryName(), installed, release.Repo, release.TagOrLatest())
+	}
+
+	return nil
+}
+
+func loadReleaseState() (map[string]string, error) {
+	state := map[string]string{}
+
+	content, err := os.ReadFile(releaseStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", releaseStatePath(), err)
+	}
+
+	return state, nil
+}
+
+func saveReleaseState(state map[string]string) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode release state: %w", err)
+	}
+
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return WriteContentToFile(string(content), releaseStatePath())
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serveRelease serves a GitHub release of example/tool with a tool asset
+// and, when checksums isn't empty, a checksums.txt holding it.
+func serveRelease(t *testing.T, checksums string) {
+	t.Helper()
+
+	binary := "#!/bin/sh\necho tool\n"
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/example/tool/releases/latest":
+			assets := []githubAsset{{Name: "tool", URL: server.URL + "/download/tool"}}
+			if checksums != "" {
+				assets = append(assets, githubAsset{Name: "checksums.txt", URL: server.URL + "/download/checksums.txt"})
+			}
+			json.NewEncoder(w).Encode(githubRelease{TagName: "v1.0.0", Assets: assets})
+		case "/download/tool":
+			w.Write([]byte(binary))
+		case "/download/checksums.txt":
+			w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	previous := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = previous })
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInstallRelease(t *testing.T) {
+	serveRelease(t, sha256Hex("#!/bin/sh\necho tool\n")+"  tool\n")
+
+	release := GitHubRelease{Repo: "example/tool", Asset: "tool", Checksums: "checksums.txt"}
+	if err := InstallRelease(release); err != nil {
+		t.Fatalf("InstallRelease failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(LocalBinPath(), "tool"))
+	if err != nil {
+		t.Fatalf("tool wasn't installed: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("tool isn't executable: %v", info.Mode())
+	}
+
+	state, err := loadReleaseState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state["tool"] != "v1.0.0" {
+		t.Errorf("recorded tool %q, want v1.0.0", state["tool"])
+	}
+}
+
+func TestInstallReleaseChecksumMismatch(t *testing.T) {
+	serveRelease(t, sha256Hex("something else")+"  tool\n")
+
+	release := GitHubRelease{Repo: "example/tool", Asset: "tool", Checksums: "checksums.txt"}
+	err := InstallRelease(release)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("InstallRelease returned %v, want a checksum mismatch", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(LocalBinPath(), "tool")); !os.IsNotExist(err) {
+		t.Errorf("tool was installed despite the mismatch")
+	}
+}
+
+func TestInstallReleaseWithoutChecksum(t *testing.T) {
+	serveRelease(t, "")
+
+	release := GitHubRelease{Repo: "example/tool", Asset: "tool"}
+	if err := InstallRelease(release); err == nil {
+		t.Fatal("InstallRelease installed a release it couldn't verify")
+	}
+
+	release.Insecure = true
+	if err := InstallRelease(release); err != nil {
+		t.Fatalf("InstallRelease failed for an insecure release: %v", err)
+	}
+}
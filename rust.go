@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+const rustupInstallScript = "https://sh.rustup.rs"
+
+// RustSettings configures the toolchain rustup installs.
+type RustSettings struct {
+	// Toolchain is the default toolchain, e.g. stable or 1.80.0
+	Toolchain string `yaml:"toolchain"`
+	// Profile is the rustup profile: minimal, default or complete
+	Profile    string   `yaml:"profile"`
+	Components []string `yaml:"components"`
+	// Targets are extra compilation targets, e.g. wasm32-unknown-unknown
+	Targets []string `yaml:"targets"`
+}
+
+func DefaultRustSettings() RustSettings {
+	return RustSettings{
+		Toolchain:  "stable",
+		Profile:    "minimal",
+		Components: []string{"clippy", "rustfmt", "rust-analyzer"},
+	}
+}
+
+func CargoHomePath() string {
+	if dir := os.Getenv("CARGO_HOME"); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(HomePath(), ".cargo")
+}
+
+// SetupRust installs rustup and the configured toolchain with its
+// components and targets, and loads cargo's environment in the shells.
+func SetupRust() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	settings := config.Rust
+
+	rustup := filepath.Join(CargoHomePath(), "bin", "rustup")
+	if _, err := os.Stat(rustup); os.IsNotExist(err) {
+		// The rc files are wired up below rather than by rustup-init. The
+		// script runs in sh since Alpine has no bash
+		color.Blue("Installing rustup...")
+		script := fmt.Sprintf("curl --proto '=https' --tlsv1.2 -sSf %s | sh -s -- -y --no-modify-path --default-toolchain %s --profile %s",
+			rustupInstallScript, settings.Toolchain, settings.Profile)
+		if err := RunPOSIXShell(script); err != nil {
+			return err
+		}
+	} else {
+		color.Blue("Installing Rust %s...", settings.Toolchain)
+		if err := RunCommand(rustup, "toolchain install", settings.Toolchain, "--profile", settings.Profile); err != nil {
+			return err
+		}
+		if err := RunCommand(rustup, "default", settings.Toolchain); err != nil {
+			return err
+		}
+	}
+
+	if len(settings.Components) > 0 {
+		color.Blue("Adding Rust components...")
+		args := append([]string{rustup, "component add", "--toolchain", settings.Toolchain}, settings.Components...)
+		if err := RunCommand(args...); err != nil {
+			return err
+		}
+	}
+
+	if len(settings.Targets) > 0 {
+		color.Blue("Adding Rust targets...")
+		args := append([]string{rustup, "target add", "--toolchain", settings.Toolchain}, settings.Targets...)
+		if err := RunCommand(args...); err != nil {
+			return err
+		}
+	}
+
+	// Same line rustup-init itself writes, so an earlier install isn't
+	// sourced twice
+	env := `. "$HOME/.cargo/env"`
+	if os.Getenv("CARGO_HOME") != "" {
+		env = fmt.Sprintf(". %q", filepath.Join(CargoHomePath(), "env"))
+	}
+
+	return AddToRCFiles(env)
+}
@@ -0,0 +1,13 @@
+package scripts
+
+// StarshipConfigTemplate renders starship.toml from the starship settings in
+// the devtools config.
+const StarshipConfigTemplate = `# Generated by devtools, edit the starship section of the devtools config instead
+add_newline = {{.AddNewline}}
+command_timeout = {{.CommandTimeout}}
+{{- range .Disabled}}
+
+[{{.}}]
+disabled = true
+{{- end}}
+`
@@ -1,60 +1,79 @@
 package scripts
 
+import "fmt"
 
-const TmuxSessionizer = `#!/usr/bin/env bash
-if [[ $# -eq 1 ]]; then
-    selected=$1
-else
-    selected=$(find ~/dev -maxdepth 1 -type d -exec find {} -maxdepth 1 -type d \; | sed '1d' | fzf)
-fi
 
-if [[ -z $selected ]]; then
-    exit 0
-fi
+// TmuxSessionizer returns a shim that forwards to `devtools sessionize`, so
+// the tmux bindings keep working with the path of the devtools executable.
+func TmuxSessionizer(executable string) string {
+  return fmt.Sprintf("#!/bin/sh\nexec '%s' sessionize \"$@\"\n", executable)
+}
 
-selected_name=$(basename "$selected" | tr . _)
-tmux_running=$(pgrep tmux)
-
-if [[ -z $TMUX ]] && [[ -z $tmux_running ]]; then
-    tmux new-session -s $selected_name -c $selected
-    exit 0
-fi
-
-if ! tmux has-session -t=$selected_name 2> /dev/null; then
-    tmux new-session -ds $selected_name -c $selected
-fi
-
-tmux switch-client -t $selected_name
-`
-
-const TmuxConfig = `set -ga terminal-overrides ",screen-256color*:Tc"
+// TmuxConfigTemplate renders a tmux.conf from the tmux settings in the
+// devtools config. Keys go through the key function so that ones tmux would
+// misparse, like ';', get quoted.
+const TmuxConfigTemplate = `set -ga terminal-overrides ",screen-256color*:Tc"
 set-option -g default-terminal "screen-256color"
-set -g status-style 'bg=#333333 fg=#5eacd3'
-set -g mouse on
+{{- if .Prefix}}
 
-# Start windows and panes at 1, not 0
-set -g base-index 1
-setw -g pane-base-index 1
+unbind C-b
+set -g prefix {{.Prefix}}
+bind {{.Prefix}} send-prefix
+{{- end}}
+set -g status-style 'bg={{.StatusBackground}} fg={{.StatusForeground}}'
+set -g mouse {{if .Mouse}}on{{else}}off{{end}}
+
+# Start windows and panes at {{.BaseIndex}}
+set -g base-index {{.BaseIndex}}
+setw -g pane-base-index {{.BaseIndex}}
 
 bind-key -r f run-shell "tmux neww ~/.local/bin/tmux-sessionizer"
+{{- if .ViMode}}
 
 set-window-option -g mode-keys vi
 bind -T copy-mode-vi v send-keys -X begin-selection
-bind -T copy-mode-vi y send-keys -X copy-pipe-and-cancel 'xclip -in -selection clipboard'
+{{- if .CopyCommand}}
+bind -T copy-mode-vi y send-keys -X copy-pipe-and-cancel '{{.CopyCommand}}'
+{{- else}}
+set -s set-clipboard on
+bind -T copy-mode-vi y send-keys -X copy-selection-and-cancel
+{{- end}}
+{{- end}}
+{{- with .PaneNavigation}}
 
 # vim-like pane switching
-bind -r j select-pane -L
-bind -r k select-pane -D
-bind -r l select-pane -U
-bind -r ';' select-pane -R
-
-bind -T copy-mode-vi j send-keys -X cursor-left
-bind -T copy-mode-vi k send-keys -X cursor-down
-bind -T copy-mode-vi l send-keys -X cursor-up
-bind -T copy-mode-vi ';' send-keys -X cursor-right
-
-bind-key -r j run-shell "~/.local/bin/tmux-sessionizer ~/dev/databyte/api"
-bind-key -r k run-shell "~/.local/bin/tmux-sessionizer ~/dev/databyte/scrapers"
-bind-key -r l run-shell "~/.local/bin/tmux-sessionizer ~/dev/databyte/ui"
-bind-key -r ';' run-shell "~/.local/bin/tmux-sessionizer ~/dev/lazydocker"
+bind -r {{key .Left}} select-pane -L
+bind -r {{key .Down}} select-pane -D
+bind -r {{key .Up}} select-pane -U
+bind -r {{key .Right}} select-pane -R
+{{- if $.ViMode}}
+
+bind -T copy-mode-vi {{key .Left}} send-keys -X cursor-left
+bind -T copy-mode-vi {{key .Down}} send-keys -X cursor-down
+bind -T copy-mode-vi {{key .Up}} send-keys -X cursor-up
+bind -T copy-mode-vi {{key .Right}} send-keys -X cursor-right
+{{- end}}
+{{- end}}
+{{template "bookmarks" .Bookmarks}}
+{{- if .TPM}}
+# Plugins, installed by TPM
+set -g @plugin 'tmux-plugins/tpm'
+{{- range .Plugins}}
+set -g @plugin '{{.}}'
+{{- end}}
+
+# Keep this line at the very bottom of tmux.conf
+run '~/.tmux/plugins/tpm/tpm'
+{{end}}`
+
+// TmuxBookmarksTemplate renders the project bookmark bindings. The markers
+// let `devtools bookmark` regenerate the section in an existing tmux.conf.
+// Paths go through the shellarg function since they may hold spaces or
+// quotes.
+const TmuxBookmarksTemplate = `
+# devtools bookmarks begin
+{{- range .}}
+bind-key -r {{key .Key}} run-shell "~/.local/bin/tmux-sessionizer {{shellarg .Path}}"
+{{- end}}
+# devtools bookmarks end
 `
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	. "github.com/tedraykov/devtools/scripts"
+)
+
+// Sessionize opens or switches to a tmux session for path, letting the user
+// pick a project when path is empty.
+func Sessionize(config *Config, path string) error {
+	if path == "" {
+		projects, err := DiscoverProjects(config.Sessionizer)
+		if err != nil {
+			return err
+		}
+
+		usage, err := LoadUsage()
+		if err != nil {
+			return err
+		}
+		usage.Rank(projects, time.Now())
+
+		if path, err = FuzzyFind(projects); err != nil {
+			return err
+		}
+
+		if path == "" {
+			return nil
+		}
+	}
+
+	path, err := filepath.Abs(ExpandHome(path))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	if err := RecordUsage(path, time.Now()); err != nil {
+		color.Red("Error recording session usage: %v", err)
+	}
+
+	return OpenSession(SessionName(path), path)
+}
+
+// SessionName derives a tmux session name from a project path. tmux treats
+// dots and colons as target separators, so they are replaced.
+func SessionName(path string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(filepath.Base(path))
+}
+
+// OpenSession attaches to the named session, creating it in dir first if it
+// doesn't exist. Inside tmux it switches the current client instead.
+func OpenSession(name, dir string) error {
+	exists := exec.Command("tmux", "has-session", "-t="+name).Run() == nil
+
+	if !exists {
+		if err := CreateSession(name, dir); err != nil {
+			return err
+		}
+	}
+
+	if os.Getenv("TMUX") == "" {
+		return runTmux("attach-session", "-t", name)
+	}
+
+	return runTmux("switch-client", "-t", name)
+}
+
+func runTmux(args ...string) error {
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// tmuxOutput runs a tmux command and returns its trimmed output.
+func tmuxOutput(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux %s: %w", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// installTmuxSessionizer installs the tmux-sessionizer shim that the tmux
+// config binds to, pointing it at this devtools executable.
+func installTmuxSessionizer() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate devtools executable: %w", err)
+	}
+
+	if err := os.MkdirAll(LocalBinPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", LocalBinPath(), err)
+	}
+
+	color.Blue("Installing tmux-sessionizer script...")
+	scriptPath := filepath.Join(LocalBinPath(), "tmux-sessionizer")
+	if err := WriteContentToFile(TmuxSessionizer(executable), scriptPath); err != nil {
+		return err
+	}
+
+	return MakeExecutable(scriptPath)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const (
+	shellsPath = "/etc/shells"
+	passwdPath = "/etc/passwd"
+)
+
+// SetLoginShell makes the named shell the login shell of the user running
+// devtools, even when it runs under sudo, and verifies the change.
+func SetLoginShell(name string) error {
+	path, err := ResolveShell(name)
+	if err != nil {
+		return err
+	}
+
+	username, err := LoginUser()
+	if err != nil {
+		return err
+	}
+
+	if current, err := LoginShell(username); err == nil && current == path {
+		fmt.Printf("%s is already the login shell of %s, skipping.\n", path, username)
+		return nil
+	}
+
+	// chsh refuses shells that aren't listed in /etc/shells
+	if err := EnsureListedShell(path); err != nil {
+		return err
+	}
+
+	color.Blue("Changing the login shell of %s to %s...", username, path)
+	if _, err := exec.LookPath("chsh"); err == nil {
+		if err := RunCmd(PrivilegedCommand("chsh", "-s", path, username)); err != nil {
+			return err
+		}
+	} else if err := setPasswdShell(username, path); err != nil {
+		// busybox systems like Alpine ship without chsh
+		return err
+	}
+
+	current, err := LoginShell(username)
+	if err != nil {
+		return fmt.Errorf("failed to verify the login shell of %s: %w", username, err)
+	}
+	if current != path {
+		return fmt.Errorf("login shell of %s is still %s", username, current)
+	}
+
+	return nil
+}
+
+// ResolveShell returns the absolute path of the shell that was installed,
+// so a freshly installed Homebrew zsh wins over the system /bin/zsh.
+func ResolveShell(name string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		if prefix, err := exec.Command("brew", "--prefix").Output(); err == nil {
+			path := filepath.Join(strings.TrimSpace(string(prefix)), "bin", name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s: %w", name, err)
+	}
+
+	return filepath.Abs(path)
+}
+
+// LoginUser returns the user whose shell should change: the user that
+// invoked sudo when running under it, the current user otherwise.
+func LoginUser() (string, error) {
+	if username := os.Getenv("SUDO_USER"); username != "" {
+		return username, nil
+	}
+
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to look up current user: %w", err)
+	}
+
+	return current.Username, nil
+}
+
+// LoginShell returns the login shell of username from the user database.
+func LoginShell(username string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		out, err := exec.Command("dscl", ".", "-read", "/Users/"+username, "UserShell").Output()
+		if err != nil {
+			return "", err
+		}
+
+		_, shell, _ := strings.Cut(strings.TrimSpace(string(out)), ":")
+		return strings.TrimSpace(shell), nil
+	}
+
+	entry, err := exec.Command("getent", "passwd", username).Output()
+	if err != nil {
+		// Fall back to reading the file when there's no getent
+		content, err := os.ReadFile(passwdPath)
+		if err != nil {
+			return "", err
+		}
+		entry = content
+	}
+
+	for _, line := range strings.Split(string(entry), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) == 7 && fields[0] == username {
+			return fields[6], nil
+		}
+	}
+
+	return "", fmt.Errorf("user %s not found", username)
+}
+
+// EnsureListedShell adds path to /etc/shells when it isn't listed.
+func EnsureListedShell(path string) error {
+	content, err := os.ReadFile(shellsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", shellsPath, err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == path {
+			return nil
+		}
+	}
+
+	color.Blue("Adding %s to %s...", path, shellsPath)
+	cmd := PrivilegedCommand("tee", "-a", shellsPath)
+	cmd.Stdin = strings.NewReader(path + "\n")
+	cmd.Stdout = io.Discard
+	return RunCmd(cmd)
+}
+
+// setPasswdShell rewrites the shell field of username in /etc/passwd.
+func setPasswdShell(username, shell string) error {
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("changing the login shell without chsh requires running as root")
+	}
+
+	content, err := os.ReadFile(passwdPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", passwdPath, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		fields := strings.Split(line, ":")
+		if len(fields) == 7 && fields[0] == username {
+			fields[6] = shell
+			lines[i] = strings.Join(fields, ":")
+		}
+	}
+
+	return WriteContentToFile(strings.Join(lines, "\n"), passwdPath)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+	. "github.com/tedraykov/devtools/scripts"
+)
+
+const starshipInstallScript = "https://starship.rs/install.sh"
+
+// StarshipSettings configures the generated starship.toml.
+type StarshipSettings struct {
+	AddNewline bool `yaml:"add_newline"`
+	// CommandTimeout is how long starship waits for commands, in milliseconds
+	CommandTimeout int `yaml:"command_timeout"`
+	// Disabled lists the modules to turn off, e.g. aws or gcloud
+	Disabled []string `yaml:"disabled"`
+}
+
+func DefaultStarshipSettings() StarshipSettings {
+	return StarshipSettings{
+		AddNewline:     true,
+		CommandTimeout: 500,
+	}
+}
+
+func StarshipConfigPath() string {
+	return filepath.Join(HomePath(), ".config", "starship.toml")
+}
+
+var starshipConfigTemplate = template.Must(template.New("starship.toml").Parse(StarshipConfigTemplate))
+
+// shellInit returns the rc file line that evaluates the output of command
+// run for each shell, e.g. `eval "$(zoxide init zsh)"`.
+func shellInit(command string) func(shell string) string {
+	return func(shell string) string {
+		if shell == "fish" {
+			return fmt.Sprintf("%s fish | source", command)
+		}
+
+		return fmt.Sprintf(`eval "$(%s %s)"`, command, shell)
+	}
+}
+
+// shellPath returns the line adding dir to PATH for each shell, so tools
+// installed there can be found by the init lines that follow it.
+func shellPath(dir string) func(shell string) string {
+	return func(shell string) string {
+		if shell == "fish" {
+			return "fish_add_path -a " + dir
+		}
+
+		return "export PATH=$PATH:" + dir
+	}
+}
+
+// InstallStarshipScript installs starship with its official script, for
+// platforms whose package manager doesn't carry it. LocalBinPath is added
+// to PATH ahead of the starship init line.
+func InstallStarshipScript() error {
+	if err := os.MkdirAll(LocalBinPath(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", LocalBinPath(), err)
+	}
+
+	if err := RunShell(fmt.Sprintf("curl -sS %s | sh -s -- -y -b %s", starshipInstallScript, LocalBinPath())); err != nil {
+		return err
+	}
+
+	return AddShellInit(shellPath(LocalBinPath()))
+}
+
+// ConfigureStarship writes starship.toml and initializes starship in the
+// shell rc files.
+func ConfigureStarship() error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if err := starshipConfigTemplate.Execute(&b, config.Starship); err != nil {
+		return fmt.Errorf("failed to render starship config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(StarshipConfigPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(StarshipConfigPath()), err)
+	}
+
+	color.Blue("Configuring starship...")
+	if err := WriteContentToFile(b.String(), StarshipConfigPath()); err != nil {
+		return err
+	}
+
+	return AddShellInit(shellInit("starship init"))
+}
+
+func ConfigureZoxide() error {
+	color.Blue("Configuring zoxide...")
+	return AddShellInit(shellInit("zoxide init"))
+}
+
+func ConfigureDirenv() error {
+	color.Blue("Configuring direnv...")
+	return AddShellInit(shellInit("direnv hook"))
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+	. "github.com/tedraykov/devtools/scripts"
+)
+
+// TmuxSettings is the structured form of the generated tmux.conf.
+type TmuxSettings struct {
+	// Prefix replaces the default C-b prefix when set, e.g. C-a
+	Prefix           string `yaml:"prefix"`
+	Mouse            bool   `yaml:"mouse"`
+	BaseIndex        int    `yaml:"base_index"`
+	StatusBackground string `yaml:"status_bg"`
+	StatusForeground string `yaml:"status_fg"`
+	ViMode           bool   `yaml:"vi_mode"`
+	// PaneNavigation overrides the keys taken from the keymap preset
+	PaneNavigation PaneKeys `yaml:"pane_navigation"`
+	// CopyCommand receives text yanked in copy mode. Empty detects the
+	// clipboard at install time.
+	CopyCommand string `yaml:"copy_command"`
+	// InstallClipboardHelper installs the detected clipboard's helper, like
+	// wl-clipboard or xclip, when it's missing
+	InstallClipboardHelper bool       `yaml:"install_clipboard_helper"`
+	Bookmarks              []Bookmark `yaml:"bookmarks"`
+	// TPM installs the tmux plugin manager along with Plugins
+	TPM bool `yaml:"tpm"`
+	// Plugins are TPM plugin specs, e.g. tmux-plugins/tmux-resurrect
+	Plugins []string `yaml:"plugins"`
+}
+
+// PaneKeys are the keys that move between panes and, in vi mode, move the
+// cursor in copy mode.
+type PaneKeys struct {
+	Left  string `yaml:"left"`
+	Down  string `yaml:"down"`
+	Up    string `yaml:"up"`
+	Right string `yaml:"right"`
+}
+
+// Bookmark binds a key to opening the session of a project.
+type Bookmark struct {
+	Key  string `yaml:"key"`
+	Path string `yaml:"path"`
+}
+
+func DefaultTmuxSettings() TmuxSettings {
+	return TmuxSettings{
+		Mouse:            true,
+		BaseIndex:        1,
+		StatusBackground: "#333333",
+		StatusForeground: "#5eacd3",
+		ViMode:           true,
+		Plugins: []string{
+			"tmux-plugins/tmux-resurrect",
+			"tmux-plugins/tmux-continuum",
+			"tmux-plugins/tmux-yank",
+		},
+	}
+}
+
+func TmuxConfigPath() string {
+	return filepath.Join(HomePath(), ".tmux.conf")
+}
+
+const (
+	tmuxBookmarksBegin = "# devtools bookmarks begin"
+	tmuxBookmarksEnd   = "# devtools bookmarks end"
+)
+
+var tmuxConfigTemplate = template.Must(template.Must(template.New("tmux.conf").Funcs(template.FuncMap{
+	"key":      tmuxKey,
+	"shellarg": tmuxShellArg,
+}).Parse(TmuxConfigTemplate)).New("bookmarks").Parse(TmuxBookmarksTemplate))
+
+// RenderTmuxConfig renders settings into the contents of a tmux.conf.
+func RenderTmuxConfig(settings TmuxSettings) (string, error) {
+	var b strings.Builder
+	if err := tmuxConfigTemplate.ExecuteTemplate(&b, "tmux.conf", settings); err != nil {
+		return "", fmt.Errorf("failed to render tmux config: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// tmuxKey quotes keys that tmux would otherwise read as syntax. Keys with
+// a single quote go in double quotes, which need \, " and $ escaped.
+func tmuxKey(key string) string {
+	if strings.Contains(key, "'") {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(key) + `"`
+	}
+
+	if strings.ContainsAny(key, ";#{}\" ") {
+		return "'" + key + "'"
+	}
+
+	return key
+}
+
+// tmuxShellArg quotes arg as a single shell word, then escapes it for a
+// double-quoted tmux string that run-shell also expands as a format.
+func tmuxShellArg(arg string) string {
+	quoted := "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		`#`, `##`,
+	).Replace(quoted)
+}
+
+// paneNavigation returns the pane keys from the tmux settings, falling
+// back to the keymap preset.
+func paneNavigation(config *Config) (PaneKeys, error) {
+	if config.Tmux.PaneNavigation != (PaneKeys{}) {
+		return config.Tmux.PaneNavigation, nil
+	}
+
+	keymap, err := config.KeymapPreset()
+	if err != nil {
+		return PaneKeys{}, err
+	}
+
+	return keymap.PaneKeys(), nil
+}
+
+// tmuxSessionizerKey opens the sessionizer, as bound in TmuxConfigTemplate.
+const tmuxSessionizerKey = "f"
+
+// reservedTmuxKeys returns the keys the generated tmux.conf binds in the
+// prefix table, which bookmarks must not take over.
+func reservedTmuxKeys(config *Config) ([]string, error) {
+	panes, err := paneNavigation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{tmuxSessionizerKey, panes.Left, panes.Down, panes.Up, panes.Right}
+	if config.Tmux.Prefix != "" {
+		keys = append(keys, config.Tmux.Prefix)
+	}
+
+	return keys, nil
+}
+
+// ConfigureTmux writes ~/.tmux.conf from the tmux settings in the devtools
+// config, detecting the clipboard unless the config sets a copy command.
+func ConfigureTmux(pm PackageManager) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	settings := config.Tmux
+	settings.PaneNavigation, err = paneNavigation(config)
+	if err != nil {
+		return err
+	}
+
+	if settings.CopyCommand == "" {
+		clipboard := DetectClipboard(pm, settings.InstallClipboardHelper)
+		color.Blue("Using %s clipboard for tmux copy mode", clipboard.Name)
+		settings.CopyCommand = clipboard.Command
+	}
+
+	content, err := RenderTmuxConfig(settings)
+	if err != nil {
+		return err
+	}
+
+	color.Blue("Configuring tmux...")
+	if err := WriteContentToFile(content, TmuxConfigPath()); err != nil {
+		return err
+	}
+
+	return InstallTmuxPlugins(settings)
+}
+
+// UpdateTmuxBookmarks regenerates the bookmark section of an existing
+// tmux.conf, leaving the rest of the file as it is. The section is appended
+// when the file doesn't have one yet.
+func UpdateTmuxBookmarks(bookmarks []Bookmark) error {
+	var b strings.Builder
+	if err := tmuxConfigTemplate.ExecuteTemplate(&b, "bookmarks", bookmarks); err != nil {
+		return fmt.Errorf("failed to render tmux bookmarks: %w", err)
+	}
+	section := strings.TrimPrefix(b.String(), "\n")
+
+	content, err := os.ReadFile(TmuxConfigPath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read tmux config: %w", err)
+	}
+	config := string(content)
+
+	begin := strings.Index(config, tmuxBookmarksBegin)
+	end := strings.Index(config, tmuxBookmarksEnd)
+	if begin != -1 && end > begin {
+		end += len(tmuxBookmarksEnd)
+		if end < len(config) && config[end] == '\n' {
+			end++
+		}
+		config = config[:begin] + section + config[end:]
+	} else {
+		if config != "" && !strings.HasSuffix(config, "\n\n") {
+			config = strings.TrimSuffix(config, "\n") + "\n\n"
+		}
+		config += section
+	}
+
+	return WriteContentToFile(config, TmuxConfigPath())
+}
+
+// ReloadTmux makes a running tmux server pick up the tmux config again,
+// first unbinding keys the config no longer binds since sourcing the file
+// won't. It does nothing when no server is running.
+func ReloadTmux(unbind ...string) error {
+	if exec.Command("tmux", "list-sessions").Run() != nil {
+		return nil
+	}
+
+	for _, key := range unbind {
+		if err := runTmux("unbind-key", key); err != nil {
+			return err
+		}
+	}
+
+	color.Blue("Reloading tmux config...")
+	return runTmux("source-file", TmuxConfigPath())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestTmuxBookmarksQuotePaths(t *testing.T) {
+	path := `/home/me/my "odd" project's $dir #1 \ end`
+
+	var b strings.Builder
+	if err := tmuxConfigTemplate.ExecuteTemplate(&b, "bookmarks", []Bookmark{{Key: "H", Path: path}}); err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := `bind-key -r H run-shell "~/.local/bin/tmux-sessionizer `
+	var command string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			command = strings.TrimSuffix(rest, `"`)
+		}
+	}
+	if command == "" {
+		t.Fatalf("no binding for H in:\n%s", b.String())
+	}
+
+	// Undo what tmux does to the double-quoted string and the format
+	// before the shell sees it
+	var arg strings.Builder
+	for i := 0; i < len(command); i++ {
+		switch {
+		case command[i] == '\\' && i+1 < len(command):
+			i++
+		case command[i] == '"' || command[i] == '$':
+			t.Fatalf("unescaped %q in %s", command[i], command)
+		case strings.HasPrefix(command[i:], "##"):
+			i++
+		}
+		arg.WriteByte(command[i])
+	}
+
+	out, err := exec.Command("sh", "-c", "printf %s "+arg.String()).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != path {
+		t.Errorf("shell saw %q, want %q", out, path)
+	}
+}
+
+func TestTmuxKey(t *testing.T) {
+	tests := map[string]string{
+		"a":   "a",
+		"C-a": "C-a",
+		";":   "';'",
+		"#":   "'#'",
+		`"`:   `'"'`,
+		"'":   `"'"`,
+	}
+
+	for key, want := range tests {
+		if got := tmuxKey(key); got != want {
+			t.Errorf("tmuxKey(%q) = %s, want %s", key, got, want)
+		}
+	}
+}
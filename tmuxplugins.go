@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const tpmRepository = "https://github.com/tmux-plugins/tpm"
+
+func TmuxPluginsPath() string {
+	return filepath.Join(HomePath(), ".tmux", "plugins")
+}
+
+func tmuxPluginStatePath() string {
+	return filepath.Join(StateDir(), "tmux-plugins.json")
+}
+
+// TmuxPluginDir returns the directory TPM installs a plugin spec like
+// user/repo#branch into.
+func TmuxPluginDir(plugin string) string {
+	plugin, _, _ = strings.Cut(plugin, "#")
+	return filepath.Join(TmuxPluginsPath(), filepath.Base(plugin))
+}
+
+// InstallTmuxPlugins installs TPM and the configured plugins, and removes
+// plugins devtools installed before that are no longer configured.
+func InstallTmuxPlugins(settings TmuxSettings) error {
+	previous, err := loadTmuxPluginState()
+	if err != nil {
+		return err
+	}
+
+	plugins := []string{}
+	if settings.TPM {
+		plugins = settings.Plugins
+	}
+
+	if err := removeTmuxPlugins(previous, plugins); err != nil {
+		return err
+	}
+
+	// Only plugins devtools installs itself are tracked, so ones installed
+	// by hand are never removed
+	tracked := map[string]bool{}
+	for _, plugin := range previous {
+		tracked[TmuxPluginDir(plugin)] = true
+	}
+	for _, plugin := range plugins {
+		if _, err := os.Stat(TmuxPluginDir(plugin)); os.IsNotExist(err) {
+			tracked[TmuxPluginDir(plugin)] = true
+		}
+	}
+
+	if settings.TPM {
+		if err := installTPM(); err != nil {
+			return err
+		}
+
+		// TPM's install script starts a tmux server on its own, so this
+		// works without an attached client
+		color.Blue("Installing tmux plugins...")
+		if err := RunCmd(exec.Command(filepath.Join(TmuxPluginDir("tpm"), "bin", "install_plugins"))); err != nil {
+			return err
+		}
+	}
+
+	installed := []string{}
+	for _, plugin := range plugins {
+		if tracked[TmuxPluginDir(plugin)] {
+			installed = append(installed, plugin)
+		}
+	}
+
+	return saveTmuxPluginState(installed)
+}
+
+func installTPM() error {
+	if _, err := os.Stat(TmuxPluginDir("tpm")); err == nil {
+		return nil
+	}
+
+	color.Blue("Installing TPM...")
+	return RunCmd(exec.Command("git", "clone", "--depth", "1", tpmRepository, TmuxPluginDir("tpm")))
+}
+
+// removeTmuxPlugins deletes the previously installed plugins missing from
+// plugins. Plugins the user installed by hand aren't tracked and are left
+// alone.
+func removeTmuxPlugins(previous, plugins []string) error {
+	keep := map[string]bool{}
+	for _, plugin := range plugins {
+		keep[TmuxPluginDir(plugin)] = true
+	}
+
+	for _, plugin := range previous {
+		dir := TmuxPluginDir(plugin)
+		if keep[dir] {
+			continue
+		}
+
+		color.Blue("Removing tmux plugin %s...", plugin)
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove tmux plugin %s: %w", plugin, err)
+		}
+	}
+
+	return nil
+}
+
+func loadTmuxPluginState() ([]string, error) {
+	plugins := []string{}
+
+	content, err := os.ReadFile(tmuxPluginStatePath())
+	if os.IsNotExist(err) {
+		return plugins, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tmux plugin state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &plugins); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", tmuxPluginStatePath(), err)
+	}
+
+	return plugins, nil
+}
+
+func saveTmuxPluginState(plugins []string) error {
+	content, err := json.MarshalIndent(plugins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tmux plugin state: %w", err)
+	}
+
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return WriteContentToFile(string(content), tmuxPluginStatePath())
+}
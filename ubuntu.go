@@ -4,24 +4,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
-	. "github.com/tedraykov/devtools/scripts"
 )
 
-
+// ubuntuPackages maps tools to the apt packages that can be installed
+// together in a single batch before the per-tool setup runs.
+var ubuntuPackages = map[string][]string{
+  "zsh":    {"zsh"},
+  "make":   {"make"},
+  "gcc":    {"gcc"},
+  "unzip":  {"unzip"},
+  "tmux":   {"tmux"},
+  "python": {"python3", "pipx"},
+  "poetry": {"pipx"},
+  "zoxide": {"zoxide"},
+  "direnv": {"direnv"},
+}
 
 type UbuntuTools struct {
 	tools []string
+	pm    PackageManager
+	// custom are the custom tools that can be selected
+	custom []CustomTool
+	// wsl adjusts the plan for Ubuntu running under WSL
+	wsl bool
 }
 
 func (t *UbuntuTools) Run() error {
-  t.runCommand("sudo", "apt", "update")
+  t.pm.Update()
+
+  if err := t.pm.Install(BatchPackages(t.tools, ubuntuPackages)...); err != nil {
+    return err
+  }
 
 	for _, tool := range t.tools {
 		color.Blue("Setting up %s...", tool)
@@ -53,8 +70,18 @@ func (t *UbuntuTools) Run() error {
       err = t.InstallPoetry()
     case "bitwarden":
       err = t.InstallBitwarden()
+    case "starship":
+      err = t.InstallStarship()
+    case "zoxide":
+      err = t.InstallZoxide()
+    case "direnv":
+      err = t.InstallDirenv()
+    case "rust":
+      err = t.InstallRust()
+    case "releases":
+      err = t.InstallReleases()
     default:
-      color.Red("Error: %s is not a valid tool", tool)
+      err = InstallCustomTool(t.pm, "Ubuntu", tool, t.custom)
     }
 
 		if err != nil {
@@ -70,48 +97,33 @@ func (t *UbuntuTools) Run() error {
 
 func (u *UbuntuTools) InstallNeovim() error {
 	color.Blue("Removing Vim if installed...")
-	if err := u.runCommand("sudo apt remove -y vim vim-runtime gvim"); err != nil {
-		return err
-	}
-
-	color.Blue("Downloading latest Neovim...")
-	if err := u.runCommand("curl -LO https://github.com/neovim/neovim/releases/download/0.9.5/nvim-linux64.tar.gz"); err != nil {
-		return err
-	}
-
-	color.Blue("Extracting Neovim...")
-	if err := u.runCommand("tar xzf nvim-linux64.tar.gz"); err != nil {
-		return err
-	}
-
-	color.Blue("Moving and renaming Neovim executable to /usr/local/bin/vim...")
-	if err := u.runCommand("sudo mv nvim-linux64/bin/nvim /usr/local/bin/vim"); err != nil {
+	if err := u.pm.Remove("vim", "vim-runtime", "gvim"); err != nil {
 		return err
 	}
 
-	color.Blue("Setting correct permissions...")
-	if err := u.runCommand("sudo chmod +x /usr/local/bin/vim"); err != nil {
+	color.Blue("Installing Neovim...")
+	if err := SetupNeovim(); err != nil {
 		return err
 	}
 
-	color.Blue("Cleaning up...")
-	if err := u.runCommand("rm -rf nvim-linux64 nvim-linux64.tar.gz"); err != nil {
+	// The nvim aliases have to come before a system vi or vim
+	if err := PrependToPath(LocalBinPath()); err != nil {
 		return err
 	}
 
-	color.Green("Neovim installation complete. You can now use 'vim' to run Neovim.")
+	color.Green("Neovim installation complete.")
 
   return u.ConfigureNeovim()
 }
 
 func (u *UbuntuTools) InstallZsh() error {
     fmt.Println("Installing Zsh...")
-    if err := u.runCommand("sudo apt install -y zsh"); err != nil {
+    if err := u.pm.Install("zsh"); err != nil {
         return err
     }
 
     // Change default shell to Zsh
-    if err := u.runCommand("sudo chsh -s /bin/zsh"); err != nil {
+    if err := SetLoginShell("zsh"); err != nil {
         return err
     }
 
@@ -125,30 +137,43 @@ func (u *UbuntuTools) InstallZsh() error {
 
 func (u *UbuntuTools) InstallGcc() error {
     fmt.Println("Installing GCC...")
-    return u.runCommand("sudo apt install -y gcc")
+    return u.pm.Install("gcc")
 }
 
 func (u *UbuntuTools) InstallMake() error {
     fmt.Println("Installing Make...")
-    return u.runCommand("sudo apt install -y make")
+    return u.pm.Install("make")
 }
 
 func (u *UbuntuTools) InstallRipgrep() error {
     fmt.Println("Installing Ripgrep...")
-    return u.runCommand("sudo apt install -y ripgrep")
+    return u.pm.Install("ripgrep")
 }
 
 func (u *UbuntuTools) InstallUnzip() error {
     fmt.Println("Installing Unzip...")
-    return u.runCommand("sudo apt install -y unzip")
+    return u.pm.Install("unzip")
 }
 
 func (u *UbuntuTools) InstallOhMyZsh() error {
-    fmt.Println("Installing Oh My Zsh...")
-    return u.runCommand("curl -fsSL https://raw.github.com/ohmyzsh/ohmyzsh/master/tools/install.sh")
+    return SetupOhMyZsh()
 }
 
 func (u *UbuntuTools) InstallDocker() error {
+    if u.wsl {
+        if DockerDesktopIntegrated() {
+            color.Yellow("Docker Desktop WSL integration detected, skipping Docker Engine installation.")
+            return nil
+        }
+
+        // Docker Engine needs systemd to run as a service under WSL
+        if !SystemdRunning() {
+            if err := EnableWSLSystemd(); err != nil {
+                return err
+            }
+        }
+    }
+
     fmt.Println("Installing Docker...")
     cmds := [][]string{
         {"sudo apt-get install ca-certificates curl"},
@@ -186,31 +211,15 @@ func (u *UbuntuTools) InstallDocker() error {
 
 func (u *UbuntuTools) InstallTmux() error {
     fmt.Println("Installing Tmux...")
-    u.runCommand("sudo apt install -y tmux")
-
-
-    fmt.Println("Installing fzf...")
-    u.runCommand("sudo apt install -y fzf")
-
-
-    tmuxSessionizerScriptPath := filepath.Join(LocalBinPath(), "tmux-sessionizer")
-    tmuxConfigPath := filepath.Join(HomePath(), ".tmux.conf")
-
-    fmt.Println("Installing tmux-sessionizer script...")
-    if err := WriteContentToFile(TmuxSessionizer, tmuxSessionizerScriptPath); err != nil {
+    if err := u.pm.Install("tmux"); err != nil {
         return err
     }
 
-    if err := MakeExecutable(tmuxSessionizerScriptPath); err != nil {
+    if err := installTmuxSessionizer(); err != nil {
         return err
     }
 
-    fmt.Println("Configuring tmux...")
-    if err := WriteContentToFile(TmuxConfig, tmuxConfigPath); err != nil {
-        return err
-  }
-
-    return nil
+    return ConfigureTmux(u.pm)
 }
 
 func (u *UbuntuTools) getLastestGoVersion() (string, error) {
@@ -249,48 +258,29 @@ func (u *UbuntuTools) InstallGo() error {
     version, err := u.getLastestGoVersion(); if err != nil {
         return err
     }
-    filename := fmt.Sprintf("go%s.linux-amd64.tar.gz", version)
 
     fmt.Printf("Installing Go %s...\n", version)
 
-    // Construct the download URL
-    downloadURL := fmt.Sprintf("https://go.dev/dl/%s",filename)
-
-    // Download the Go tarball
-    if err = u.runCommand("curl", "-LO", downloadURL); err != nil {
-        return  err
-    }
-
-    // Remove existing Go installation and extract the new one
-    err = u.runCommand("sudo rm -rf /usr/local/go")
-    err = u.runCommand("sudo tar -C /usr/local -xzf", filename)
-
-    // Add Go binary directory to PATH
-    if err := AddToRCFiles("export PATH=$PATH:/usr/local/go/bin"); err != nil {
+    // Install side by side with other versions and switch to it
+    if err := UseGoVersion(version); err != nil {
         return err
     }
 
-    // Clean up
-    if err := DeleteFile(filename); err != nil {
-      return err
-    }
-
-    return nil
+    return SetupGoTools()
 }
 
 func (u *UbuntuTools) InstallNode() error {
-    fmt.Println("Installing NVM...")
-    u.runCommand("curl -o- https://raw.githubusercontent.com/nvm-sh/nvm/v0.39.1/install.sh")
-
-    u.runCommand("source ~/.zshrc")
-    u.runCommand("nvm install --lts")
-
-    return nil
+    color.Blue("Installing Node...")
+    return SetupNode()
 }
 
 func (u *UbuntuTools) InstallPython() error {
     fmt.Println("Installing Python...")
-    return u.runCommand("sudo apt install -y python3")
+    if err := u.pm.Install(ubuntuPackages["python"]...); err != nil {
+        return err
+    }
+
+    return SetupPython()
 }
 
 func (u *UbuntuTools) ConfigureNeovim() error {
@@ -305,24 +295,55 @@ func (u *UbuntuTools) ConfigureNeovim() error {
 
 func (u *UbuntuTools) InstallPoetry() error {
     fmt.Println("Installing Poetry...")
-    return u.runCommand("curl -sSL https://install.python-poetry.org | python3 -")
+    if err := u.pm.Install(ubuntuPackages["poetry"]...); err != nil {
+        return err
+    }
+
+    return SetupPoetry()
 }
 
 func (u *UbuntuTools) InstallBitwarden() error {
     fmt.Println("Installing Bitwarden...")
-    return u.runCommand("npm install -g @bitwarden/cli")
+    return InstallNpmPackages("@bitwarden/cli")
 }
 
-func (u *UbuntuTools) runCommand(args ...string) error {
-    splitArgs := []string{}
+func (u *UbuntuTools) InstallStarship() error {
+    color.Blue("Installing Starship...")
+    if err := InstallStarshipScript(); err != nil {
+        return err
+    }
 
-    for _, arg := range args {
-        splitArgs = append(splitArgs, strings.Split(arg, " ")...)
+    return ConfigureStarship()
+}
+
+func (u *UbuntuTools) InstallZoxide() error {
+    color.Blue("Installing Zoxide...")
+    if err := u.pm.Install(ubuntuPackages["zoxide"]...); err != nil {
+        return err
+    }
+
+    return ConfigureZoxide()
+}
+
+func (u *UbuntuTools) InstallDirenv() error {
+    color.Blue("Installing Direnv...")
+    if err := u.pm.Install(ubuntuPackages["direnv"]...); err != nil {
+        return err
     }
 
-    fmt.Printf("Running command: %s\n", strings.Join(splitArgs, " "))
-    cmd := exec.Command(splitArgs[0], splitArgs[1:]...)
-    cmd.Stdout = os.Stdout
-    cmd.Stderr = os.Stderr
-    return cmd.Run()
+    return ConfigureDirenv()
+}
+
+func (u *UbuntuTools) InstallRust() error {
+    color.Blue("Installing Rust...")
+    return SetupRust()
+}
+
+func (u *UbuntuTools) InstallReleases() error {
+    color.Blue("Installing GitHub releases...")
+    return SetupReleases()
+}
+
+func (u *UbuntuTools) runCommand(args ...string) error {
+    return RunCommand(args...)
 }
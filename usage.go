@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ProjectUsage is how often and how recently a project's session was opened.
+type ProjectUsage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Usage records session usage per project path, for frecency ranking.
+type Usage map[string]ProjectUsage
+
+func UsagePath() string {
+	return filepath.Join(StateDir(), "sessions.json")
+}
+
+func LoadUsage() (Usage, error) {
+	usage := Usage{}
+
+	content, err := os.ReadFile(UsagePath())
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session usage: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", UsagePath(), err)
+	}
+
+	return usage, nil
+}
+
+func (u Usage) Save() error {
+	content, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session usage: %w", err)
+	}
+
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return WriteContentToFile(string(content), UsagePath())
+}
+
+// RecordUsage counts a session being opened for path.
+func RecordUsage(path string, now time.Time) error {
+	usage, err := LoadUsage()
+	if err != nil {
+		return err
+	}
+
+	entry := usage[path]
+	entry.Count++
+	entry.LastUsed = now
+	usage[path] = entry
+
+	return usage.Save()
+}
+
+// Frecency scores a project by use count weighted by how recently it was
+// last used, the same buckets zoxide uses.
+func (u Usage) Frecency(path string, now time.Time) float64 {
+	entry, ok := u[path]
+	if !ok {
+		return 0
+	}
+
+	age := now.Sub(entry.LastUsed)
+	switch {
+	case age < time.Hour:
+		return float64(entry.Count) * 4
+	case age < 24*time.Hour:
+		return float64(entry.Count) * 2
+	case age < 7*24*time.Hour:
+		return float64(entry.Count) / 2
+	default:
+		return float64(entry.Count) / 4
+	}
+}
+
+// Rank sorts projects by frecency, keeping discovery order for ties.
+func (u Usage) Rank(projects []string, now time.Time) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		return u.Frecency(projects[i], now) > u.Frecency(projects[j], now)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -45,31 +46,111 @@ func AddToRCFiles(content string) error {
   rcFiles := []string{".bashrc", ".zshrc"}
 
 	for _, rcFile := range rcFiles {
+		if err := AddToRCFile(rcFile, content); err != nil {
+			return err
+		}
+  }
+
+  return nil
+}
+
+// shellRCFiles are the rc files of the shells devtools wires tools into,
+// relative to the home path.
+var shellRCFiles = []struct {
+	shell  string
+	rcFile string
+}{
+	{"bash", ".bashrc"},
+	{"zsh", ".zshrc"},
+	{"fish", filepath.Join(".config", "fish", "config.fish")},
+}
+
+// AddShellInit adds the line returned by init for each shell to that
+// shell's rc file, for tools whose init code differs between shells.
+func AddShellInit(init func(shell string) string) error {
+	for _, rc := range shellRCFiles {
+		if err := AddToRCFile(rc.rcFile, init(rc.shell)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddToRCFile appends content to an rc file in the home path unless it's
+// already there. Missing rc files are skipped.
+func AddToRCFile(rcFile, content string) error {
+	filePath := filepath.Join(HomePath(), rcFile)
+
+	// Check if the file exists
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		fmt.Printf("%s does not exist, skipping.\n", rcFile)
+		return nil
+	}
+
+	// Check if content is already in the file
+	if exists, err := ContentExists(filePath, content); err != nil {
+		return fmt.Errorf("error checking %s: %w", rcFile, err)
+	} else if exists {
+		fmt.Printf("Content already exists in %s, skipping.\n", rcFile)
+		return nil
+	}
+
+	// Append content to the file
+	if err := AppendToFile(filePath, content); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", rcFile, err)
+	}
+
+	fmt.Printf("Successfully added content to %s\n", rcFile)
+
+	return nil
+}
+
+// RemoveFromRCFiles removes the lines matching content from the bash and
+// zsh rc files in the home path. Missing rc files are skipped.
+func RemoveFromRCFiles(content string) error {
+	for _, rcFile := range []string{".bashrc", ".zshrc"} {
 		filePath := filepath.Join(HomePath(), rcFile)
 
-		// Check if the file exists
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			fmt.Printf("%s does not exist, skipping.\n", rcFile)
+		existing, err := os.ReadFile(filePath)
+		if os.IsNotExist(err) {
 			continue
 		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rcFile, err)
+		}
 
-		// Check if content is already in the file
-		if exists, err := ContentExists(filePath, content); err != nil {
-			return fmt.Errorf("error checking %s: %w", rcFile, err)
-		} else if exists {
-			fmt.Printf("Content already exists in %s, skipping.\n", rcFile)
+		lines := strings.SplitAfter(string(existing), "\n")
+		kept := lines[:0]
+		for _, line := range lines {
+			if strings.TrimSpace(line) != strings.TrimSpace(content) {
+				kept = append(kept, line)
+			}
+		}
+		if len(kept) == len(lines) {
 			continue
 		}
 
-		// Append content to the file
-		if err := AppendToFile(filePath, content); err != nil {
-			return fmt.Errorf("failed to append to %s: %w", rcFile, err)
+		if err := WriteContentToFile(strings.Join(kept, ""), filePath); err != nil {
+			return err
 		}
+		fmt.Printf("Removed %s from %s\n", strings.TrimSpace(content), rcFile)
+	}
 
-		fmt.Printf("Successfully added content to %s\n", rcFile)
-  }
+	return nil
+}
 
-  return nil
+// PrependToPath adds dir to the front of PATH in the rc files, so it wins
+// over system installs of the same tool. Lines that appended dir, or the
+// stale directories, to PATH are removed.
+func PrependToPath(dir string, stale ...string) error {
+	for _, old := range append([]string{dir}, stale...) {
+		if err := RemoveFromRCFiles("export PATH=$PATH:" + old); err != nil {
+			return err
+		}
+	}
+
+	return AddToRCFiles("export PATH=" + dir + ":$PATH")
 }
 
 func ContentExists(filePath, content string) (bool, error) {
@@ -114,3 +195,85 @@ func DeleteFile(path string) error {
 
   return nil
 }
+
+// RunCommand runs a command, splitting each argument on spaces, and streams
+// its output to the terminal.
+func RunCommand(args ...string) error {
+	splitArgs := []string{}
+
+	for _, arg := range args {
+		splitArgs = append(splitArgs, strings.Split(arg, " ")...)
+	}
+
+	fmt.Printf("Running command: %s\n", strings.Join(splitArgs, " "))
+	cmd := exec.Command(splitArgs[0], splitArgs[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// RunShell runs a script with bash so that shell syntax and functions like
+// source, pipes and redirects work.
+func RunShell(script string) error {
+	fmt.Printf("Running script: %s\n", script)
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// RunCmd runs cmd and streams its output to the terminal like RunCommand,
+// but keeps the arguments as they are, so paths with spaces stay whole.
+func RunCmd(cmd *exec.Cmd) error {
+	fmt.Printf("Running command: %s\n", strings.Join(cmd.Args, " "))
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	return cmd.Run()
+}
+
+// RunPOSIXShell runs a script with sh, for install scripts that have to
+// work where bash isn't installed, like stock Alpine.
+func RunPOSIXShell(script string) error {
+	fmt.Printf("Running script: %s\n", script)
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Privileged prefixes a command with sudo unless devtools is already running
+// as root, which is the norm in containers where sudo isn't installed.
+func Privileged(command string) string {
+	if os.Geteuid() == 0 {
+		return command
+	}
+
+	return "sudo " + command
+}
+
+// PrivilegedCommand is exec.Command run through sudo unless devtools is
+// already running as root, like Privileged.
+func PrivilegedCommand(name string, args ...string) *exec.Cmd {
+	if os.Geteuid() == 0 {
+		return exec.Command(name, args...)
+	}
+
+	return exec.Command("sudo", append([]string{name}, args...)...)
+}
+
+// ExpandHome replaces a leading ~ in path with the home directory.
+func ExpandHome(path string) string {
+	if path == "~" {
+		return HomePath()
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(HomePath(), path[2:])
+	}
+
+	return path
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const wslConfPath = "/etc/wsl.conf"
+
+// IsWSL reports whether devtools is running inside Windows Subsystem for Linux.
+func IsWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+
+	version, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(version)), "microsoft")
+}
+
+// DockerDesktopIntegrated reports whether Docker Desktop's WSL integration
+// already provides a docker CLI and engine for this distro.
+func DockerDesktopIntegrated() bool {
+	if _, err := os.Stat("/mnt/wsl/docker-desktop"); err == nil {
+		return true
+	}
+
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		return false
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	return strings.HasPrefix(path, "/mnt/")
+}
+
+// WSLCopyCommand returns the command that copies stdin to the Windows
+// clipboard, preferring win32yank since clip.exe mangles unicode.
+func WSLCopyCommand() string {
+	if _, err := exec.LookPath("win32yank.exe"); err == nil {
+		return "win32yank.exe -i --crlf"
+	}
+
+	return "clip.exe"
+}
+
+// SystemdRunning reports whether systemd is the init process.
+func SystemdRunning() bool {
+	comm, err := os.ReadFile("/proc/1/comm")
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(comm)) == "systemd"
+}
+
+// EnableWSLSystemd turns on systemd in /etc/wsl.conf. It only takes effect
+// after the distro is restarted with `wsl --shutdown`.
+func EnableWSLSystemd() error {
+	content, err := os.ReadFile(wslConfPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", wslConfPath, err)
+	}
+
+	updated := SetINIValue(string(content), "boot", "systemd", "true")
+	if updated == string(content) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp("", "wsl.conf")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	tmp.Close()
+
+	if err := WriteContentToFile(updated, tmp.Name()); err != nil {
+		return err
+	}
+
+	color.Blue("Enabling systemd in %s...", wslConfPath)
+	if err := RunCommand("sudo install -m 0644", tmp.Name(), wslConfPath); err != nil {
+		return err
+	}
+
+	color.Yellow("Run `wsl --shutdown` from Windows and reopen the distro for systemd to start.")
+	return nil
+}
+
+// SetINIValue sets key to value in section of an ini file, adding the
+// section or key when they're missing. content is returned as is when the
+// key already has value, whatever the whitespace around the =.
+func SetINIValue(content, section, key, value string) string {
+	lines := []string{}
+	if content != "" {
+		lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	}
+
+	header := "[" + section + "]"
+	entry := key + "=" + value
+	inSection := false
+	sectionEnd := -1
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "[") {
+			inSection = trimmed == header
+			if inSection {
+				sectionEnd = i + 1
+			}
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		// New keys go after the last line of the section that isn't blank
+		if trimmed != "" {
+			sectionEnd = i + 1
+		}
+		if k, v, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(k) == key {
+			if strings.TrimSpace(v) == value {
+				return content
+			}
+			lines[i] = entry
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+
+	if sectionEnd == -1 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, header, entry)
+	} else {
+		lines = append(lines[:sectionEnd], append([]string{entry}, lines[sectionEnd:]...)...)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
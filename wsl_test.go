@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestSetINIValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "empty file",
+			content: "",
+			want:    "[boot]\nsystemd=true\n",
+		},
+		{
+			name:    "missing section",
+			content: "[network]\nhostname=dev\n",
+			want:    "[network]\nhostname=dev\n\n[boot]\nsystemd=true\n",
+		},
+		{
+			name:    "missing key before a blank line",
+			content: "[boot]\ncommand=echo hi\n\n[network]\nhostname=dev\n",
+			want:    "[boot]\ncommand=echo hi\nsystemd=true\n\n[network]\nhostname=dev\n",
+		},
+		{
+			name:    "different value",
+			content: "[boot]\nsystemd=false\n",
+			want:    "[boot]\nsystemd=true\n",
+		},
+		{
+			name:    "same value with spaces",
+			content: "[boot]\n  systemd = true\n",
+			want:    "[boot]\n  systemd = true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetINIValue(tt.content, "boot", "systemd", "true"); got != tt.want {
+				t.Errorf("SetINIValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}